@@ -0,0 +1,222 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// AEAD is a cipher mode providing authenticated encryption with associated data, eg. AES-GCM, ChaCha20-Poly1305.
+// It allows to write algorithm-agnostic code and swap ciphers by configuration.
+type AEAD interface {
+	// NonceSize returns the size of the nonce that must be passed to Seal and Open.
+	NonceSize() int
+
+	// Overhead returns the maximum difference between the lengths of a plaintext and its ciphertext.
+	Overhead() int
+
+	// Seal encrypts and authenticates plaintext, authenticates the additional data aad and returns the ciphertext.
+	// The nonce must be NonceSize() bytes long and unique for all time, for a given key.
+	Seal(nonce, plaintext, aad []byte) []byte
+
+	// Open decrypts and authenticates ciphertext, authenticates the additional data aad and,
+	// if successful, returns the plaintext. The nonce and aad must match the values passed to Seal.
+	// The error of the AEADs returned by the package wraps ErrDecryptionFailed.
+	Open(nonce, ciphertext, aad []byte) ([]byte, error)
+}
+
+type aead struct {
+	aead cipher.AEAD
+}
+
+func (a *aead) NonceSize() int {
+	return a.aead.NonceSize()
+}
+
+func (a *aead) Overhead() int {
+	return a.aead.Overhead()
+}
+
+func (a *aead) Seal(nonce, plaintext, aad []byte) []byte {
+	return a.aead.Seal(nil, nonce, plaintext, aad)
+}
+
+func (a *aead) Open(nonce, ciphertext, aad []byte) ([]byte, error) {
+	plaintext, err := a.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	return plaintext, nil
+}
+
+// NewAesGcm returns an AES-GCM AEAD, len(key) should be 16, 24 or 32.
+func NewAesGcm(key []byte) (AEAD, error) {
+	block, err := NewAesCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aead{aead: gcm}, nil
+}
+
+// NewChacha20Poly1305 returns a ChaCha20-Poly1305 AEAD, len(key) should be 32.
+func NewChacha20Poly1305(key []byte) (AEAD, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("chacha20poly1305: %w (must be 32 bytes)", ErrInvalidKeySize)
+	}
+
+	c, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aead{aead: c}, nil
+}
+
+// NewXchacha20Poly1305 returns a XChaCha20-Poly1305 AEAD, len(key) should be 32.
+// It uses 24 bytes nonces, which are large enough to be generated randomly without the risk of collision.
+func NewXchacha20Poly1305(key []byte) (AEAD, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305: %w (must be 32 bytes)", ErrInvalidKeySize)
+	}
+
+	c, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aead{aead: c}, nil
+}
+
+// Xchacha20Poly1305Encrypt encrypt data with key use XChaCha20-Poly1305 algorithm, len(key) should be 32.
+// A random 24 bytes nonce is generated and prepended to the returned ciphertext.
+func Xchacha20Poly1305Encrypt(data, key []byte) ([]byte, error) {
+	x, err := NewXchacha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, x.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305: failed to generate nonce: %w", err)
+	}
+
+	return append(nonce, x.Seal(nonce, data, nil)...), nil
+}
+
+// Xchacha20Poly1305Decrypt decrypt data encrypted by Xchacha20Poly1305Encrypt with key.
+// It returns an error if the key is invalid or the data is tampered.
+func Xchacha20Poly1305Decrypt(data, key []byte) ([]byte, error) {
+	x, err := NewXchacha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := x.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("xchacha20poly1305: %w", ErrCiphertextTooShort)
+	}
+
+	plaintext, err := x.Open(data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// The algorithm tags of the envelope created by SealEnvelope.
+const (
+	EnvelopeAesGcm           = "aesgcm"
+	EnvelopeChacha20Poly1305 = "chacha20poly1305"
+)
+
+const envelopeVersion = "v1"
+
+// newEnvelopeAEAD returns the AEAD of the envelope algorithm tag.
+func newEnvelopeAEAD(alg string, key []byte) (AEAD, error) {
+	switch alg {
+	case EnvelopeAesGcm:
+		return NewAesGcm(key)
+	case EnvelopeChacha20Poly1305:
+		return NewChacha20Poly1305(key)
+	default:
+		return nil, fmt.Errorf("envelope: unsupported algorithm %q", alg)
+	}
+}
+
+// SealEnvelope encrypts plaintext with the AEAD algorithm alg and returns a self-describing envelope string
+// "v1.<alg>.<nonce>.<ciphertext>", the nonce and ciphertext are encoded with the URL-safe base64 alphabet without padding.
+// alg should be EnvelopeAesGcm or EnvelopeChacha20Poly1305, the version and algorithm tag are authenticated with aad,
+// so the envelope can be decrypted by OpenEnvelope without knowing the algorithm in advance.
+func SealEnvelope(alg string, key, plaintext, aad []byte) (string, error) {
+	aead, err := newEnvelopeAEAD(alg, key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	header := envelopeVersion + "." + alg
+	ciphertext := aead.Seal(nonce, plaintext, envelopeAAD(header, aad))
+
+	return header + "." + base64.RawURLEncoding.EncodeToString(nonce) + "." + base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// OpenEnvelope decrypts the envelope created by SealEnvelope with key and aad, the algorithm is parsed from the envelope.
+func OpenEnvelope(key []byte, envelope string, aad []byte) ([]byte, error) {
+	parts := strings.Split(envelope, ".")
+	if len(parts) != 4 {
+		return nil, errors.New("envelope: invalid format")
+	}
+
+	if parts[0] != envelopeVersion {
+		return nil, fmt.Errorf("envelope: unsupported version %q", parts[0])
+	}
+
+	aead, err := newEnvelopeAEAD(parts[1], key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("envelope: failed to decode nonce: %w", err)
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("envelope: invalid nonce size")
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("envelope: failed to decode ciphertext: %w", err)
+	}
+
+	header := parts[0] + "." + parts[1]
+
+	return aead.Open(nonce, ciphertext, envelopeAAD(header, aad))
+}
+
+// envelopeAAD returns the additional data authenticating both the envelope header and the aad of the caller.
+func envelopeAAD(header string, aad []byte) []byte {
+	return append([]byte(header+"."), aad...)
+}
@@ -0,0 +1,178 @@
+package cryptor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestAEAD(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAEAD")
+
+	aesGcm, err := NewAesGcm([]byte("abcdefghijklmnop"))
+	assert.IsNil(err)
+
+	chacha, err := NewChacha20Poly1305([]byte("abcdefghijklmnopqrstuvwxyz012345"))
+	assert.IsNil(err)
+
+	for _, c := range []AEAD{aesGcm, chacha} {
+		nonce := make([]byte, c.NonceSize())
+		plaintext := []byte("hello world")
+		aad := []byte("header")
+
+		ciphertext := c.Seal(nonce, plaintext, aad)
+
+		decrypted, err := c.Open(nonce, ciphertext, aad)
+		assert.IsNil(err)
+		assert.Equal(plaintext, decrypted)
+
+		_, err = c.Open(nonce, ciphertext, []byte("other header"))
+		assert.IsNotNil(err)
+
+		ciphertext[0] ^= 0xff
+		_, err = c.Open(nonce, ciphertext, aad)
+		assert.IsNotNil(err)
+	}
+}
+
+func TestNewAesGcm(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestNewAesGcm")
+
+	_, err := NewAesGcm([]byte("short key"))
+	assert.IsNotNil(err)
+
+	// the ciphertext is compatible with AesGcmDecrypt.
+	aesGcm, err := NewAesGcm([]byte("abcdefghijklmnop"))
+	assert.IsNil(err)
+
+	nonce := []byte("0123456789ab")
+	encrypted := append(nonce, aesGcm.Seal(nonce, []byte("hello"), nil)...)
+	assert.Equal("hello", string(AesGcmDecrypt(encrypted, []byte("abcdefghijklmnop"))))
+}
+
+func TestNewChacha20Poly1305(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestNewChacha20Poly1305")
+
+	_, err := NewChacha20Poly1305([]byte("abcdefghijklmnop"))
+	assert.IsNotNil(err)
+
+	chacha, err := NewChacha20Poly1305([]byte("abcdefghijklmnopqrstuvwxyz012345"))
+	assert.IsNil(err)
+	assert.Equal(12, chacha.NonceSize())
+}
+
+func TestNewXchacha20Poly1305(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestNewXchacha20Poly1305")
+
+	_, err := NewXchacha20Poly1305([]byte("abcdefghijklmnop"))
+	assert.IsNotNil(err)
+
+	x, err := NewXchacha20Poly1305([]byte("abcdefghijklmnopqrstuvwxyz012345"))
+	assert.IsNil(err)
+	assert.Equal(24, x.NonceSize())
+	assert.Equal(16, x.Overhead())
+}
+
+func TestXchacha20Poly1305Encrypt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestXchacha20Poly1305Encrypt")
+
+	key := []byte("abcdefghijklmnopqrstuvwxyz012345")
+	data := []byte("hello world")
+
+	encrypted, err := Xchacha20Poly1305Encrypt(data, key)
+	assert.IsNil(err)
+	assert.Equal(24+len(data)+16, len(encrypted))
+
+	decrypted, err := Xchacha20Poly1305Decrypt(encrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	// random nonce
+	other, _ := Xchacha20Poly1305Encrypt(data, key)
+	assert.NotEqual(encrypted, other)
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = Xchacha20Poly1305Decrypt(tampered, key)
+	assert.IsNotNil(err)
+
+	_, err = Xchacha20Poly1305Decrypt(encrypted, []byte("abcdefghijklmnopqrstuvwxyz543210"))
+	assert.IsNotNil(err)
+
+	_, err = Xchacha20Poly1305Decrypt(encrypted[:10], key)
+	assert.IsNotNil(err)
+
+	_, err = Xchacha20Poly1305Encrypt(data, []byte("short"))
+	assert.IsNotNil(err)
+}
+
+func TestSealEnvelope(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSealEnvelope")
+
+	aesKey := []byte("abcdefghijklmnop")
+	chachaKey := []byte("abcdefghijklmnopqrstuvwxyz012345")
+	aad := []byte("user:1")
+
+	for alg, key := range map[string][]byte{EnvelopeAesGcm: aesKey, EnvelopeChacha20Poly1305: chachaKey} {
+		envelope, err := SealEnvelope(alg, key, []byte("hello world"), aad)
+		assert.IsNil(err)
+		assert.Equal(true, strings.HasPrefix(envelope, "v1."+alg+"."))
+
+		plaintext, err := OpenEnvelope(key, envelope, aad)
+		assert.IsNil(err)
+		assert.Equal("hello world", string(plaintext))
+
+		_, err = OpenEnvelope(key, envelope, []byte("user:2"))
+		assert.IsNotNil(err)
+	}
+
+	_, err := SealEnvelope("des", aesKey, []byte("hello"), nil)
+	assert.IsNotNil(err)
+
+	_, err = SealEnvelope(EnvelopeChacha20Poly1305, aesKey, []byte("hello"), nil)
+	assert.IsNotNil(err)
+}
+
+func TestOpenEnvelopeInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestOpenEnvelopeInvalid")
+
+	key := []byte("abcdefghijklmnopqrstuvwxyz012345")
+
+	envelope, err := SealEnvelope(EnvelopeAesGcm, key, []byte("hello"), nil)
+	assert.IsNil(err)
+
+	parts := strings.Split(envelope, ".")
+
+	invalid := []string{
+		"",
+		"v1.aesgcm.abc",
+		"v2." + strings.Join(parts[1:], "."),
+		"v1.unknown." + strings.Join(parts[2:], "."),
+		// the algorithm tag is authenticated
+		"v1.chacha20poly1305." + strings.Join(parts[2:], "."),
+		"v1.aesgcm.!!!." + parts[3],
+		"v1.aesgcm." + parts[2] + ".!!!",
+		"v1.aesgcm.YWJj." + parts[3],
+		"v1.aesgcm." + parts[2] + "." + parts[3][:len(parts[3])-2],
+	}
+
+	for _, e := range invalid {
+		_, err := OpenEnvelope(key, e, nil)
+		assert.IsNotNil(err)
+	}
+}
@@ -14,8 +14,12 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
 	"io"
 	"os"
+	"strings"
 )
 
 // Base64StdEncode encode string with base64 encoding.
@@ -31,6 +35,33 @@ func Base64StdDecode(s string) string {
 	return string(b)
 }
 
+// Base64Encode encode data with the standard base64 encoding, with padding.
+func Base64Encode(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Base64Decode decode the standard base64 (with padding) encoded string.
+// Unlike Base64StdDecode, it returns an error if s is not valid base64.
+func Base64Decode(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// Base64URLEncode encode data with the URL-safe base64 encoding without padding, as used by JWT.
+func Base64URLEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Base64URLDecode decode the URL-safe base64 encoded string, with or without padding.
+// It returns an error if s is not valid URL-safe base64, eg. contains '+' or '/', or its padding is invalid,
+// so every decoded value has only one unpadded and one padded encoding.
+func Base64URLDecode(s string) ([]byte, error) {
+	if strings.HasSuffix(s, "=") {
+		return base64.URLEncoding.DecodeString(s)
+	}
+
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
 // Md5String return the md5 value of string.
 // Play: https://go.dev/play/p/1bLcVetbTOI
 func Md5String(s string) string {
@@ -209,3 +240,120 @@ func Sha512WithBase64(str string) string {
 	sha512.Write([]byte(str))
 	return base64.StdEncoding.EncodeToString(sha512.Sum([]byte("")))
 }
+
+// Md5Bytes return the raw md5 digest of data.
+// MD5 is cryptographically broken, use it only for interoperating with legacy systems, not for security.
+func Md5Bytes(data []byte) []byte {
+	digest := md5.Sum(data)
+	return digest[:]
+}
+
+// Md5Hex return the md5 digest of data as lowercase hex string.
+// MD5 is cryptographically broken, use it only for interoperating with legacy systems, not for security.
+func Md5Hex(data []byte) string {
+	return hex.EncodeToString(Md5Bytes(data))
+}
+
+// Sha1Bytes return the raw sha1 digest of data.
+// SHA-1 is cryptographically weak, use it only for interoperating with legacy systems, not for security.
+func Sha1Bytes(data []byte) []byte {
+	digest := sha1.Sum(data)
+	return digest[:]
+}
+
+// Sha1Hex return the sha1 digest of data as lowercase hex string.
+// SHA-1 is cryptographically weak, use it only for interoperating with legacy systems, not for security.
+func Sha1Hex(data []byte) string {
+	return hex.EncodeToString(Sha1Bytes(data))
+}
+
+// Sha256Bytes return the raw sha256 digest of data.
+func Sha256Bytes(data []byte) []byte {
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// Sha256Hex return the sha256 digest of data as lowercase hex string.
+func Sha256Hex(data []byte) string {
+	return hex.EncodeToString(Sha256Bytes(data))
+}
+
+var crc64EcmaTable = crc64.MakeTable(crc64.ECMA)
+
+// Crc32 return the crc32 checksum (IEEE polynomial) of data. It is not a cryptographic hash.
+func Crc32(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+// Crc32WithTable return the crc32 checksum of data using the polynomial represented by table,
+// eg. crc32.MakeTable(crc32.Castagnoli).
+func Crc32WithTable(data []byte, table *crc32.Table) uint32 {
+	return crc32.Checksum(data, table)
+}
+
+// Crc32Hex return the crc32 checksum (IEEE polynomial) of data as 8 hex digits.
+func Crc32Hex(data []byte) string {
+	return fmt.Sprintf("%08x", Crc32(data))
+}
+
+// Crc64 return the crc64 checksum (ECMA polynomial) of data. It is not a cryptographic hash.
+func Crc64(data []byte) uint64 {
+	return crc64.Checksum(data, crc64EcmaTable)
+}
+
+// Crc64WithTable return the crc64 checksum of data using the polynomial represented by table,
+// eg. crc64.MakeTable(crc64.ISO).
+func Crc64WithTable(data []byte, table *crc64.Table) uint64 {
+	return crc64.Checksum(data, table)
+}
+
+// Crc64Hex return the crc64 checksum (ECMA polynomial) of data as 16 hex digits.
+func Crc64Hex(data []byte) string {
+	return fmt.Sprintf("%016x", Crc64(data))
+}
+
+// Md5Reader return the md5 digest of the data read from r until EOF, the data is hashed with a buffer,
+// so the memory used is bounded.
+func Md5Reader(r io.Reader) ([]byte, error) {
+	return hashReader(md5.New(), r)
+}
+
+// Sha1Reader return the sha1 digest of the data read from r until EOF.
+func Sha1Reader(r io.Reader) ([]byte, error) {
+	return hashReader(sha1.New(), r)
+}
+
+// Sha256Reader return the sha256 digest of the data read from r until EOF.
+func Sha256Reader(r io.Reader) ([]byte, error) {
+	return hashReader(sha256.New(), r)
+}
+
+// Sha512Reader return the sha512 digest of the data read from r until EOF.
+func Sha512Reader(r io.Reader) ([]byte, error) {
+	return hashReader(sha512.New(), r)
+}
+
+// HashFileSha256 return the sha256 hex digest of the file content.
+func HashFileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	digest, err := Sha256Reader(file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(digest), nil
+}
+
+func hashReader(h hash.Hash, r io.Reader) ([]byte, error) {
+	buf := make([]byte, 65536) // 64KB
+	if _, err := io.CopyBuffer(h, r, buf); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
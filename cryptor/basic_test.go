@@ -1,7 +1,15 @@
 package cryptor
 
 import (
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"hash/crc64"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/duke-git/lancet/v2/internal"
 )
@@ -20,6 +28,48 @@ func TestBase64StdDecode(t *testing.T) {
 	assert.Equal("hello world", Base64StdDecode("aGVsbG8gd29ybGQ="))
 }
 
+func TestBase64Encode(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestBase64Encode")
+
+	data := []byte{0xfb, 0xff, 0xfe, 'h', 'i'}
+
+	assert.Equal("+//+aGk=", Base64Encode(data))
+	assert.Equal("-__-aGk", Base64URLEncode(data))
+
+	decoded, err := Base64Decode("+//+aGk=")
+	assert.IsNil(err)
+	assert.Equal(data, decoded)
+
+	_, err = Base64Decode("+//+aGk")
+	assert.IsNotNil(err)
+
+	for _, s := range []string{"-__-aGk", "-__-aGk="} {
+		decoded, err = Base64URLDecode(s)
+		assert.IsNil(err)
+		assert.Equal(data, decoded)
+	}
+
+	_, err = Base64URLDecode("+//+aGk")
+	assert.IsNotNil(err)
+
+	// only the valid padding is accepted, so a value has no other encodings.
+	for _, s := range []string{"-__-aGk==", "-__-aGk=====", "YQ=", "YQ===", "YQ======", "="} {
+		_, err = Base64URLDecode(s)
+		assert.IsNotNil(err)
+	}
+
+	decoded, err = Base64URLDecode("YQ==")
+	assert.IsNil(err)
+	assert.Equal([]byte("a"), decoded)
+
+	assert.Equal("", Base64Encode(nil))
+	decoded, err = Base64URLDecode("")
+	assert.IsNil(err)
+	assert.Equal([]byte{}, decoded)
+}
+
 func TestMd5String(t *testing.T) {
 	t.Parallel()
 
@@ -197,6 +247,32 @@ func TestSha512(t *testing.T) {
 	assert.Equal(expected, sha512)
 }
 
+func TestDigestHex(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDigestHex")
+
+	data := []byte("hello world")
+
+	assert.Equal("5eb63bbbe01eeed093cb22bb8f5acdc3", Md5Hex(data))
+	assert.Equal("2aae6c35c94fcfb415dbe95f408b9ce91ee846ed", Sha1Hex(data))
+	assert.Equal("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", Sha256Hex(data))
+
+	// consistent with the string based functions
+	assert.Equal(Md5String("hello world"), Md5Hex(data))
+	assert.Equal(Sha1("hello world"), Sha1Hex(data))
+	assert.Equal(Sha256("hello world"), Sha256Hex(data))
+
+	assert.Equal("d41d8cd98f00b204e9800998ecf8427e", Md5Hex(nil))
+
+	assert.Equal(16, len(Md5Bytes(data)))
+	assert.Equal(20, len(Sha1Bytes(data)))
+	assert.Equal(32, len(Sha256Bytes(data)))
+	assert.Equal(Md5Hex(data), hex.EncodeToString(Md5Bytes(data)))
+	assert.Equal(Sha1Hex(data), hex.EncodeToString(Sha1Bytes(data)))
+	assert.Equal(Sha256Hex(data), hex.EncodeToString(Sha256Bytes(data)))
+}
+
 func TestSha512WithBase64(t *testing.T) {
 	t.Parallel()
 
@@ -206,3 +282,73 @@ func TestSha512WithBase64(t *testing.T) {
 	assert := internal.NewAssert(t, "TestSha512WithBase64")
 	assert.Equal(expected, str)
 }
+
+func TestCrc32(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCrc32")
+
+	data := []byte("hello world")
+
+	assert.Equal(uint32(0x0d4a1185), Crc32(data))
+	assert.Equal("0d4a1185", Crc32Hex(data))
+	assert.Equal(uint32(0xc99465aa), Crc32WithTable(data, crc32.MakeTable(crc32.Castagnoli)))
+	assert.Equal(uint32(0), Crc32([]byte{}))
+}
+
+func TestCrc64(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCrc64")
+
+	data := []byte("hello world")
+
+	assert.Equal(uint64(0x53037ecdef2352da), Crc64(data))
+	assert.Equal("53037ecdef2352da", Crc64Hex(data))
+	assert.Equal(uint64(0xb9cf3f572ad9ac3e), Crc64WithTable(data, crc64.MakeTable(crc64.ISO)))
+	assert.Equal(uint64(0), Crc64([]byte{}))
+}
+
+func TestHashReader(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestHashReader")
+
+	data := strings.Repeat("hello world", 10000)
+
+	md5Digest, err := Md5Reader(strings.NewReader(data))
+	assert.IsNil(err)
+	assert.Equal(Md5String(data), hex.EncodeToString(md5Digest))
+
+	sha1Digest, err := Sha1Reader(strings.NewReader(data))
+	assert.IsNil(err)
+	assert.Equal(Sha1(data), hex.EncodeToString(sha1Digest))
+
+	sha256Digest, err := Sha256Reader(strings.NewReader(data))
+	assert.IsNil(err)
+	assert.Equal(Sha256(data), hex.EncodeToString(sha256Digest))
+
+	sha512Digest, err := Sha512Reader(strings.NewReader(data))
+	assert.IsNil(err)
+	assert.Equal(Sha512(data), hex.EncodeToString(sha512Digest))
+
+	_, err = Sha256Reader(iotest.ErrReader(errors.New("read failed")))
+	assert.IsNotNil(err)
+}
+
+func TestHashFileSha256(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestHashFileSha256")
+
+	file := filepath.Join(t.TempDir(), "hello.txt")
+	err := os.WriteFile(file, []byte("hello world"), 0644)
+	assert.IsNil(err)
+
+	digest, err := HashFileSha256(file)
+	assert.IsNil(err)
+	assert.Equal("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", digest)
+
+	_, err = HashFileSha256(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.IsNotNil(err)
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// sha256DigestInfoPrefix is the DER encoded DigestInfo prefix of SHA-256 in EMSA-PKCS1-v1_5 encoding (RFC 8017 section 9.2).
+var sha256DigestInfoPrefix = []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+
+// RsaBlind blinds the SHA-256 PKCS #1 v1.5 encoded message of data with a random factor, so the signer can sign it
+// by RsaBlindSign without learning data. The blinded message is sent to the signer and the unblinder is kept secret
+// to recover the signature by RsaUnblind. Both are len(modulus) bytes big endian integers.
+// The unblinded signature is a normal PKCS #1 v1.5 signature of data, which can be verified by rsa.VerifyPKCS1v15
+// with crypto.SHA256 or RsaVerifySign.
+func RsaBlind(data []byte, pub *rsa.PublicKey) ([]byte, []byte, error) {
+	if err := checkRsaPublicKey(pub); err != nil {
+		return nil, nil, err
+	}
+
+	k := pub.Size()
+
+	hashed := sha256.Sum256(data)
+	em, err := pkcs1v15EncodeSHA256(hashed[:], k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, rInv, err := rsaBlindingFactor(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// blinded = m * r^e mod n
+	blinded := new(big.Int).Exp(r, big.NewInt(int64(pub.E)), pub.N)
+	blinded.Mul(blinded, new(big.Int).SetBytes(em))
+	blinded.Mod(blinded, pub.N)
+
+	return blinded.FillBytes(make([]byte, k)), rInv.FillBytes(make([]byte, k)), nil
+}
+
+// RsaBlindSign signs the blinded message returned by RsaBlind with the raw RSA private key operation.
+// The signer learns nothing about the original data, so it should authenticate the requester by other means.
+// The key must be used only for blind signing: it signs any value it is given, so a signature of it on
+// anything else, or a decryption with it, could be obtained by sending the value as a blinded message.
+// The message is blinded again by the signer with its own random factor before the exponentiation,
+// so the timing of the private key operation doesn't depend on the value chosen by the requester.
+func RsaBlindSign(blinded []byte, priv *rsa.PrivateKey) ([]byte, error) {
+	if err := checkRsaPublicKey(&priv.PublicKey); err != nil {
+		return nil, err
+	}
+
+	k := priv.Size()
+	if len(blinded) != k {
+		return nil, fmt.Errorf("rsa: invalid blinded message length %d, it should be %d", len(blinded), k)
+	}
+
+	m := new(big.Int).SetBytes(blinded)
+	if m.Sign() == 0 || m.Cmp(priv.N) >= 0 {
+		return nil, errors.New("rsa: blinded message out of range")
+	}
+
+	r, rInv, err := rsaBlindingFactor(&priv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// s = (m * r^e)^d * r^-1 mod n = m^d mod n
+	c := new(big.Int).Exp(r, big.NewInt(int64(priv.E)), priv.N)
+	c.Mul(c, m)
+	c.Mod(c, priv.N)
+
+	s := rsaPrivateExp(priv, c)
+	s.Mul(s, rInv)
+	s.Mod(s, priv.N)
+
+	// verify the result to guard against the faults of the computation leaking the key.
+	if new(big.Int).Exp(s, big.NewInt(int64(priv.E)), priv.N).Cmp(m) != 0 {
+		return nil, errors.New("rsa: blind signature verification failed")
+	}
+
+	return s.FillBytes(make([]byte, k)), nil
+}
+
+// RsaUnblind recovers the signature of the original data from the blind signature returned by RsaBlindSign and the
+// unblinder returned by RsaBlind. It verifies the signature and returns an error wrapping rsa.ErrVerification
+// if the signer didn't sign the blinded message with the private key of pub.
+func RsaUnblind(blindSig, unblinder []byte, pub *rsa.PublicKey) ([]byte, error) {
+	if err := checkRsaPublicKey(pub); err != nil {
+		return nil, err
+	}
+
+	k := pub.Size()
+	if len(blindSig) != k || len(unblinder) != k {
+		return nil, fmt.Errorf("rsa: invalid blind signature or unblinder length, it should be %d", k)
+	}
+
+	s := new(big.Int).SetBytes(blindSig)
+	s.Mul(s, new(big.Int).SetBytes(unblinder))
+	s.Mod(s, pub.N)
+
+	signature := s.FillBytes(make([]byte, k))
+
+	// the signed message is unknown here, check the signature has the valid PKCS #1 v1.5 SHA-256 structure.
+	em := new(big.Int).Exp(s, big.NewInt(int64(pub.E)), pub.N).FillBytes(make([]byte, k))
+	tLen := len(sha256DigestInfoPrefix) + sha256.Size
+	if em[0] != 0 || em[1] != 1 || em[k-tLen-1] != 0 || string(em[k-tLen:k-sha256.Size]) != string(sha256DigestInfoPrefix) {
+		return nil, fmt.Errorf("rsa: %w: invalid blind signature", rsa.ErrVerification)
+	}
+	for _, b := range em[2 : k-tLen-1] {
+		if b != 0xff {
+			return nil, fmt.Errorf("rsa: %w: invalid blind signature", rsa.ErrVerification)
+		}
+	}
+
+	return signature, nil
+}
+
+// pkcs1v15EncodeSHA256 returns the EMSA-PKCS1-v1_5 encoding of the SHA-256 digest hashed in k bytes:
+// EM = 0x00 || 0x01 || PS (0xff) || 0x00 || DigestInfo || hash.
+func pkcs1v15EncodeSHA256(hashed []byte, k int) ([]byte, error) {
+	tLen := len(sha256DigestInfoPrefix) + len(hashed)
+	if k < tLen+11 {
+		return nil, fmt.Errorf("rsa: %w: key too small for blind signature", ErrInvalidKeySize)
+	}
+
+	em := make([]byte, k)
+	em[1] = 1
+	for i := 2; i < k-tLen-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-tLen:], sha256DigestInfoPrefix)
+	copy(em[k-len(hashed):], hashed)
+
+	return em, nil
+}
+
+// rsaBlindingFactor returns a random r in (1, n) invertible mod n and its inverse.
+func rsaBlindingFactor(pub *rsa.PublicKey) (*big.Int, *big.Int, error) {
+	one := big.NewInt(1)
+
+	for {
+		r, err := rand.Int(rand.Reader, pub.N)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if r.Cmp(one) <= 0 {
+			continue
+		}
+
+		if rInv := new(big.Int).ModInverse(r, pub.N); rInv != nil {
+			return r, rInv, nil
+		}
+	}
+}
+
+// rsaPrivateExp returns c^d mod n, with the CRT of the precomputed values of a two primes key.
+func rsaPrivateExp(priv *rsa.PrivateKey, c *big.Int) *big.Int {
+	pre := priv.Precomputed
+	if len(priv.Primes) != 2 || pre.Dp == nil || pre.Dq == nil || pre.Qinv == nil {
+		return new(big.Int).Exp(c, priv.D, priv.N)
+	}
+
+	p, q := priv.Primes[0], priv.Primes[1]
+
+	// m1 = c^dP mod p, m2 = c^dQ mod q, h = qInv * (m1 - m2) mod p, m = m2 + h * q
+	m1 := new(big.Int).Exp(c, pre.Dp, p)
+	m2 := new(big.Int).Exp(c, pre.Dq, q)
+
+	h := m1.Sub(m1, m2)
+	h.Mul(h, pre.Qinv)
+	h.Mod(h, p)
+
+	return h.Mul(h, q).Add(h, m2)
+}
@@ -0,0 +1,118 @@
+package cryptor
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestRsaBlindSignature(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaBlindSignature")
+
+	privateKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+	publicKey := &privateKey.PublicKey
+
+	data := []byte("hello world")
+
+	blinded, unblinder, err := RsaBlind(data, publicKey)
+	assert.IsNil(err)
+	assert.Equal(publicKey.Size(), len(blinded))
+	assert.Equal(publicKey.Size(), len(unblinder))
+
+	// the blinding factor is random, blinding the same data twice gives different messages.
+	blinded2, _, err := RsaBlind(data, publicKey)
+	assert.IsNil(err)
+	assert.NotEqual(blinded, blinded2)
+
+	blindSig, err := RsaBlindSign(blinded, privateKey)
+	assert.IsNil(err)
+
+	signature, err := RsaUnblind(blindSig, unblinder, publicKey)
+	assert.IsNil(err)
+
+	// the unblinded signature is a normal PKCS #1 v1.5 signature of data.
+	hashed := sha256.Sum256(data)
+	assert.IsNil(rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature))
+
+	expected, err := rsa.SignPKCS1v15(nil, privateKey, crypto.SHA256, hashed[:])
+	assert.IsNil(err)
+	assert.Equal(expected, signature)
+
+	other := sha256.Sum256([]byte("hello lancet"))
+	assert.IsNotNil(rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, other[:], signature))
+}
+
+func TestRsaBlindSignatureErrors(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaBlindSignatureErrors")
+
+	privateKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+	publicKey := &privateKey.PublicKey
+
+	blinded, unblinder, err := RsaBlind([]byte("hello world"), publicKey)
+	assert.IsNil(err)
+
+	_, err = RsaBlindSign(blinded[1:], privateKey)
+	assert.IsNotNil(err)
+
+	_, err = RsaBlindSign(make([]byte, len(blinded)), privateKey)
+	assert.IsNotNil(err)
+
+	blindSig, err := RsaBlindSign(blinded, privateKey)
+	assert.IsNil(err)
+
+	_, err = RsaUnblind(blindSig[1:], unblinder, publicKey)
+	assert.IsNotNil(err)
+
+	// tampered blind signature.
+	blindSig[len(blindSig)-1] ^= 1
+	_, err = RsaUnblind(blindSig, unblinder, publicKey)
+	assert.Equal(true, errors.Is(err, rsa.ErrVerification))
+}
+
+func TestRsaBlindSignUnblindedMessage(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaBlindSignUnblindedMessage")
+
+	privateKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	hashed := sha256.Sum256([]byte("hello world"))
+	expected, err := rsa.SignPKCS1v15(nil, privateKey, crypto.SHA256, hashed[:])
+	assert.IsNil(err)
+
+	// the message chosen by the requester without blinding is the encoded message itself,
+	// the signer side blinding doesn't change the result.
+	em, err := pkcs1v15EncodeSHA256(hashed[:], privateKey.Size())
+	assert.IsNil(err)
+
+	signature, err := RsaBlindSign(em, privateKey)
+	assert.IsNil(err)
+	assert.Equal(expected, signature)
+
+	// without the precomputed CRT values.
+	plainKey := *privateKey
+	plainKey.Precomputed = rsa.PrecomputedValues{}
+
+	signature, err = RsaBlindSign(em, &plainKey)
+	assert.IsNil(err)
+	assert.Equal(expected, signature)
+
+	// the message should be less than the modulus.
+	_, err = RsaBlindSign(privateKey.N.FillBytes(make([]byte, privateKey.Size())), privateKey)
+	assert.IsNotNil(err)
+
+	_, err = RsaBlindSign(bytes.Repeat([]byte{0xff}, privateKey.Size()), privateKey)
+	assert.IsNotNil(err)
+}
@@ -4,6 +4,13 @@
 // Package cryptor implements some util functions to encrypt and decrypt.
 // Note:
 // 1. for aes crypt function, the `key` param length should be 16, 24 or 32. if not, will panic.
+// all aes modes accept any of the valid lengths and select AES-128, AES-192 or AES-256 by len(key),
+// so the same function decrypts data of any variant as long as the key is right, see DetectAesKeySize.
+// the key is used as-is by all aes modes, no padding, truncating or hashing is applied, so the result is
+// interoperable with other standard implementations, eg. openssl.
+// 2. for des crypt function, the `key` param length should be 8. if not, will panic.
+// 3. the decrypt functions panic on invalid or tampered data, for the data from untrusted source,
+// use the variants returning error instead, eg. AesGcmDecryptE.
 package cryptor
 
 import (
@@ -12,15 +19,40 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/des"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
+// NewAesCipher returns the AES cipher.Block of key for building custom modes, len(key) should be 16, 24 or 32.
+// The key is used as-is, the same as all aes crypt functions of the package, no normalization is applied.
+func NewAesCipher(key []byte) (cipher.Block, error) {
+	if !isAesKeyLengthValid(len(key)) {
+		return nil, fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
+	}
+
+	return aes.NewCipher(key)
+}
+
+// DetectAesKeySize returns the AES variant selected by key in bits, ie. 128, 192 or 256,
+// or an error if len(key) is not 16, 24 or 32.
+func DetectAesKeySize(key []byte) (int, error) {
+	if !isAesKeyLengthValid(len(key)) {
+		return 0, fmt.Errorf("aes: %w: key length %d (must be 16, 24, or 32 bytes)", ErrInvalidKeySize, len(key))
+	}
+
+	return len(key) * 8, nil
+}
+
 // AesEcbEncrypt encrypt data with key use AES ECB algorithm
 // len(key) should be 16, 24 or 32.
 // Play: https://go.dev/play/p/jT5irszHx-j
@@ -41,7 +73,7 @@ func AesEcbEncrypt(data, key []byte) []byte {
 		paddedData[i] = byte(padding)
 	}
 
-	cipher, err := aes.NewCipher(generateAesKey(key, len(key)))
+	cipher, err := aes.NewCipher(key)
 	if err != nil {
 		panic("aes: failed to create cipher: " + err.Error())
 	}
@@ -67,7 +99,7 @@ func AesEcbDecrypt(encrypted, key []byte) []byte {
 		panic("aes: encrypted data length is not a multiple of block size")
 	}
 
-	cipher, err := aes.NewCipher(generateAesKey(key, len(key)))
+	cipher, err := aes.NewCipher(key)
 	if err != nil {
 		panic("aes: failed to create cipher: " + err.Error())
 	}
@@ -152,6 +184,90 @@ func AesCbcDecrypt(encrypted, key []byte) []byte {
 	return pkcs7UnPadding(decrypted)
 }
 
+// AesCbcDecryptE is like AesCbcDecrypt, but returns an error instead of panicking if the key or encrypted is invalid.
+// It also returns an error if the PKCS#7 padding of the decrypted data is invalid.
+func AesCbcDecryptE(encrypted, key []byte) ([]byte, error) {
+	if !isAesKeyLengthValid(len(key)) {
+		return nil, fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
+	}
+
+	if len(encrypted) < 2*aes.BlockSize {
+		return nil, fmt.Errorf("aes: %w", ErrCiphertextTooShort)
+	}
+
+	if len(encrypted)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("aes: %w: ciphertext is not a multiple of the block size", ErrDecryptionFailed)
+	}
+
+	iv := encrypted[:aes.BlockSize]
+	ciphertext := encrypted[aes.BlockSize:]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New("aes: failed to create cipher: " + err.Error())
+	}
+
+	decrypted := make([]byte, len(ciphertext))
+	mode := cipher.NewCBCDecrypter(block, iv)
+	mode.CryptBlocks(decrypted, ciphertext)
+
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("aes: %w: invalid PKCS#7 padding", ErrDecryptionFailed)
+	}
+	for _, b := range decrypted[len(decrypted)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("aes: %w: invalid PKCS#7 padding content", ErrDecryptionFailed)
+		}
+	}
+
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// AesCbcHmacEncrypt encrypt data with encKey use AES CBC algorithm, then appends the HMAC-SHA256 of IV and
+// ciphertext computed with macKey (encrypt-then-MAC). The result is IV | ciphertext | MAC (32 bytes).
+// len(encKey) should be 16, 24 or 32, macKey should not be empty and should be independent of encKey.
+func AesCbcHmacEncrypt(data, encKey, macKey []byte) ([]byte, error) {
+	if !isAesKeyLengthValid(len(encKey)) {
+		return nil, fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
+	}
+
+	if len(macKey) == 0 {
+		return nil, errors.New("aes: mac key should not be empty")
+	}
+
+	encrypted := AesCbcEncrypt(data, encKey)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(encrypted)
+
+	return mac.Sum(encrypted), nil
+}
+
+// AesCbcHmacDecrypt verifies the MAC of the data encrypted by AesCbcHmacEncrypt in constant time
+// before decrypting and unpadding it, so that it is not vulnerable to the padding oracle attack.
+// It returns an error if the MAC doesn't match.
+func AesCbcHmacDecrypt(encrypted, encKey, macKey []byte) ([]byte, error) {
+	if len(macKey) == 0 {
+		return nil, errors.New("aes: mac key should not be empty")
+	}
+
+	if len(encrypted) < sha256.Size {
+		return nil, fmt.Errorf("aes: %w", ErrCiphertextTooShort)
+	}
+
+	data, tag := encrypted[:len(encrypted)-sha256.Size], encrypted[len(encrypted)-sha256.Size:]
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(data)
+
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return nil, fmt.Errorf("aes: %w: message authentication failed", ErrDecryptionFailed)
+	}
+
+	return AesCbcDecryptE(data, encKey)
+}
+
 // AesCtrCrypt encrypt data with key use AES CTR algorithm
 // len(key) should be 16, 24 or 32.
 // Play: https://go.dev/play/p/SpaZO0-5Nsp
@@ -252,12 +368,22 @@ func AesCfbEncrypt(data, key []byte) []byte {
 // len(encrypted) should be great than 16, len(key) should be 16, 24 or 32.
 // Play: https://go.dev/play/p/tfkF10B13kH
 func AesCfbDecrypt(encrypted, key []byte) []byte {
+	plaintext, err := AesCfbDecryptE(encrypted, key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return plaintext
+}
+
+// AesCfbDecryptE is like AesCfbDecrypt, but returns an error instead of panicking if the key or encrypted is invalid.
+func AesCfbDecryptE(encrypted, key []byte) ([]byte, error) {
 	if !isAesKeyLengthValid(len(key)) {
-		panic("aes: invalid key length (must be 16, 24, or 32 bytes)")
+		return nil, fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
 	}
 
 	if len(encrypted) < aes.BlockSize {
-		panic("aes: encrypted data too short")
+		return nil, fmt.Errorf("aes: %w", ErrCiphertextTooShort)
 	}
 
 	iv := encrypted[:aes.BlockSize]
@@ -265,13 +391,54 @@ func AesCfbDecrypt(encrypted, key []byte) []byte {
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		panic("aes: failed to create cipher: " + err.Error())
+		return nil, errors.New("aes: failed to create cipher: " + err.Error())
 	}
 
 	plaintext := make([]byte, len(ciphertext))
 	stream := cipher.NewCFBDecrypter(block, iv)
 	stream.XORKeyStream(plaintext, ciphertext)
 
+	return plaintext, nil
+}
+
+// AesCfb8Encrypt encrypt data with key use AES CFB algorithm with 8 bits segment size (CFB8),
+// which is used by some embedded and legacy devices. The random IV is prepended to the result.
+// CFB8 runs a block encryption for every byte, so it's about 16 times slower than AesCfbEncrypt.
+// len(key) should be 16, 24 or 32.
+func AesCfb8Encrypt(data, key []byte) []byte {
+	block, err := NewAesCipher(key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		panic("aes: failed to generate IV: " + err.Error())
+	}
+
+	ciphertext := make([]byte, len(data))
+	newCFB8Encrypter(block, iv).XORKeyStream(ciphertext, data)
+
+	return append(iv, ciphertext...)
+}
+
+// AesCfb8Decrypt decrypt data encrypted by AesCfb8Encrypt with key use AES CFB8 algorithm.
+// len(encrypted) should be great than 16, len(key) should be 16, 24 or 32.
+func AesCfb8Decrypt(encrypted, key []byte) []byte {
+	block, err := NewAesCipher(key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if len(encrypted) < aes.BlockSize {
+		panic("aes: encrypted data too short")
+	}
+
+	iv, ciphertext := encrypted[:aes.BlockSize], encrypted[aes.BlockSize:]
+
+	plaintext := make([]byte, len(ciphertext))
+	newCFB8Decrypter(block, iv).XORKeyStream(plaintext, ciphertext)
+
 	return plaintext
 }
 
@@ -327,21 +494,48 @@ func AesOfbDecrypt(data, key []byte) []byte {
 	return plaintext
 }
 
+// AesCtrEncryptStream reads data from src, encrypts it with key use AES CTR algorithm and writes the result to dst.
+// The random IV is written to the front of dst. len(key) should be 16, 24 or 32.
+func AesCtrEncryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesEncryptStream(src, dst, key, cipher.NewCTR)
+}
+
+// AesCtrDecryptStream reads the data encrypted by AesCtrEncryptStream from src, decrypts it with key and writes the result to dst.
+// len(key) should be 16, 24 or 32.
+func AesCtrDecryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesDecryptStream(src, dst, key, cipher.NewCTR)
+}
+
+// AesCfbEncryptStream reads data from src, encrypts it with key use AES CFB algorithm and writes the result to dst.
+// The random IV is written to the front of dst. len(key) should be 16, 24 or 32.
+func AesCfbEncryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesEncryptStream(src, dst, key, cipher.NewCFBEncrypter)
+}
+
+// AesCfbDecryptStream reads the data encrypted by AesCfbEncryptStream from src, decrypts it with key and writes the result to dst.
+// len(key) should be 16, 24 or 32.
+func AesCfbDecryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesDecryptStream(src, dst, key, cipher.NewCFBDecrypter)
+}
+
+// AesOfbEncryptStream reads data from src, encrypts it with key use AES OFB algorithm and writes the result to dst.
+// The random IV is written to the front of dst. len(key) should be 16, 24 or 32.
+func AesOfbEncryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesEncryptStream(src, dst, key, cipher.NewOFB)
+}
+
+// AesOfbDecryptStream reads the data encrypted by AesOfbEncryptStream from src, decrypts it with key and writes the result to dst.
+// len(key) should be 16, 24 or 32.
+func AesOfbDecryptStream(src io.Reader, dst io.Writer, key []byte) error {
+	return aesDecryptStream(src, dst, key, cipher.NewOFB)
+}
+
 // AesGcmEncrypt encrypt data with key use AES GCM algorithm
 // Play: https://go.dev/play/p/rUt0-DmsPCs
 func AesGcmEncrypt(data, key []byte) []byte {
-	if !isAesKeyLengthValid(len(key)) {
-		panic("aes: invalid key length (must be 16, 24, or 32 bytes)")
-	}
-
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		panic("aes: failed to create cipher: " + err.Error())
-	}
-
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := NewAesGcm(key)
 	if err != nil {
-		panic("aes: failed to create GCM: " + err.Error())
+		panic(err.Error())
 	}
 
 	nonce := make([]byte, gcm.NonceSize())
@@ -349,47 +543,65 @@ func AesGcmEncrypt(data, key []byte) []byte {
 		panic("aes: failed to generate nonce: " + err.Error())
 	}
 
-	ciphertext := gcm.Seal(nil, nonce, data, nil)
+	ciphertext := gcm.Seal(nonce, data, nil)
 
 	return append(nonce, ciphertext...)
 }
 
-// AesGcmDecrypt decrypt data with key use AES GCM algorithm
+// AesGcmDecrypt decrypt data with key use AES GCM algorithm.
+// It panics if data is tampered, use AesGcmDecryptE to decrypt untrusted data.
 // Play: https://go.dev/play/p/rUt0-DmsPCs
 func AesGcmDecrypt(data, key []byte) []byte {
-	if !isAesKeyLengthValid(len(key)) {
-		panic("aes: invalid key length (must be 16, 24, or 32 bytes)")
-	}
-
-	block, err := aes.NewCipher(key)
+	plaintext, err := AesGcmDecryptE(data, key)
 	if err != nil {
-		panic("aes: failed to create cipher: " + err.Error())
+		panic(err.Error())
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	return plaintext
+}
+
+// AesGcmDecryptE decrypt data encrypted by AesGcmEncrypt with key use AES GCM algorithm.
+// Unlike AesGcmDecrypt, it returns an error instead of panicking if the key is invalid or the data is tampered,
+// so it should be preferred for the data which could be controlled by an attacker.
+func AesGcmDecryptE(data, key []byte) ([]byte, error) {
+	gcm, err := NewAesGcm(key)
 	if err != nil {
-		panic("aes: failed to create GCM: " + err.Error())
+		return nil, err
 	}
 
 	nonceSize := gcm.NonceSize()
 	if len(data) < nonceSize {
-		panic("aes: ciphertext too short")
+		return nil, fmt.Errorf("aes: %w", ErrCiphertextTooShort)
 	}
 
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := gcm.Open(nonce, ciphertext, nil)
 	if err != nil {
-		panic("aes: decryption failed: " + err.Error())
+		return nil, fmt.Errorf("aes: %w", err)
 	}
 
-	return plaintext
+	return plaintext, nil
+}
+
+// NewDesCipher returns the DES cipher.Block of key for building custom modes, len(key) should be 8.
+// The key is used as-is, the same as all des crypt functions of the package, no normalization is applied.
+func NewDesCipher(key []byte) (cipher.Block, error) {
+	if len(key) != 8 {
+		return nil, fmt.Errorf("des: %w (must be 8 bytes)", ErrInvalidKeySize)
+	}
+
+	return des.NewCipher(key)
 }
 
 // DesEcbEncrypt encrypt data with key use DES ECB algorithm
 // len(key) should be 8.
 // Play: https://go.dev/play/p/8qivmPeZy4P
 func DesEcbEncrypt(data, key []byte) []byte {
-	cipher, err := des.NewCipher(generateDesKey(key))
+	if len(key) != 8 {
+		panic("des: key length must be 8 bytes")
+	}
+
+	cipher, err := des.NewCipher(key)
 	if err != nil {
 		panic("des: failed to create cipher: " + err.Error())
 	}
@@ -409,7 +621,11 @@ func DesEcbEncrypt(data, key []byte) []byte {
 // len(key) should be 8.
 // Play: https://go.dev/play/p/8qivmPeZy4P
 func DesEcbDecrypt(encrypted, key []byte) []byte {
-	cipher, err := des.NewCipher(generateDesKey(key))
+	if len(key) != 8 {
+		panic("des: key length must be 8 bytes")
+	}
+
+	cipher, err := des.NewCipher(key)
 	if err != nil {
 		panic("des: failed to create cipher: " + err.Error())
 	}
@@ -489,9 +705,8 @@ func DesCbcDecrypt(encrypted, key []byte) []byte {
 // Play: https://go.dev/play/p/9-T6OjKpcdw
 // deprecated: use DesCtrEncrypt and DesCtrDecrypt instead.
 func DesCtrCrypt(data, key []byte) []byte {
-	size := len(key)
-	if size != 8 {
-		panic("key length shoud be 8")
+	if len(key) != 8 {
+		panic("des: key length must be 8 bytes")
 	}
 
 	block, _ := des.NewCipher(key)
@@ -673,9 +888,33 @@ func DesOfbDecrypt(data, key []byte) []byte {
 	return decrypted
 }
 
+// MinRsaKeySize is the minimum rsa key size in bits accepted by the rsa key generation functions,
+// unless the AllowWeakKeys option is passed.
+const MinRsaKeySize = 2048
+
+// RsaKeyOption is for adding rsa key generation config.
+type RsaKeyOption func(*rsaKeyConfig)
+
+type rsaKeyConfig struct {
+	allowWeakKeys bool
+}
+
+// AllowWeakKeys allows to generate rsa keys smaller than MinRsaKeySize bits.
+// It should only be used for legacy interoperability or testing.
+func AllowWeakKeys() RsaKeyOption {
+	return func(c *rsaKeyConfig) {
+		c.allowWeakKeys = true
+	}
+}
+
 // GenerateRsaKey create rsa private and public pemo file.
+// It returns an error if keySize is smaller than MinRsaKeySize, unless the AllowWeakKeys option is passed.
 // Play: https://go.dev/play/p/zutRHrDqs0X
-func GenerateRsaKey(keySize int, priKeyFile, pubKeyFile string) error {
+func GenerateRsaKey(keySize int, priKeyFile, pubKeyFile string, opts ...RsaKeyOption) error {
+	if err := checkRsaKeySize(keySize, opts); err != nil {
+		return err
+	}
+
 	// private key
 	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
 	if err != nil {
@@ -797,13 +1036,77 @@ func RsaDecrypt(data []byte, privateKeyFileName string) []byte {
 	return plainText
 }
 
+// RsaEncryptBase64 encrypt data with rsa algorithm (PKCS#1 v1.5) and returns the standard base64 encoded ciphertext,
+// the public key is parsed from pubKeyPEM.
+func RsaEncryptBase64(data string, pubKeyPEM []byte) (string, error) {
+	pubKey, err := parseRsaPublicKey(pubKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	cipherText, err := rsa.EncryptPKCS1v15(rand.Reader, pubKey, []byte(data))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+// RsaDecryptBase64 decrypt the standard base64 encoded ciphertext returned by RsaEncryptBase64,
+// the private key is parsed from privKeyPEM. It returns a decode error if encoded is not valid base64,
+// or rsa.ErrDecryption if the ciphertext can't be decrypted.
+func RsaDecryptBase64(encoded string, privKeyPEM []byte) (string, error) {
+	cipherText, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("rsa: failed to decode base64 ciphertext: %w", err)
+	}
+
+	priKey, err := parseRsaPrivateKey(privKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	plainText, err := rsa.DecryptPKCS1v15(rand.Reader, priKey, cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plainText), nil
+}
+
 // GenerateRsaKeyPair create rsa private and public key.
+// It panics if keySize is smaller than MinRsaKeySize, unless the AllowWeakKeys option is passed.
 // Play: https://go.dev/play/p/sSVmkfENKMz
-func GenerateRsaKeyPair(keySize int) (*rsa.PrivateKey, *rsa.PublicKey) {
-	privateKey, _ := rsa.GenerateKey(rand.Reader, keySize)
+func GenerateRsaKeyPair(keySize int, opts ...RsaKeyOption) (*rsa.PrivateKey, *rsa.PublicKey) {
+	if err := checkRsaKeySize(keySize, opts); err != nil {
+		panic(err.Error())
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		panic("rsa: failed to generate key: " + err.Error())
+	}
+
 	return privateKey, &privateKey.PublicKey
 }
 
+// GenerateRsaKeyPairWithReader create rsa private and public key, random is used as the source of randomness.
+// Note: the same random stream doesn't guarantee the same key, and since Go 1.26 random is ignored by crypto/rsa
+// unless GODEBUG=cryptocustomrand=1 is set, which is the default for modules declaring an earlier go version.
+// It returns an error if keySize is smaller than MinRsaKeySize, unless the AllowWeakKeys option is passed.
+func GenerateRsaKeyPairWithReader(random io.Reader, keySize int, opts ...RsaKeyOption) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	if err := checkRsaKeySize(keySize, opts); err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err := rsa.GenerateKey(random, keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateKey, &privateKey.PublicKey, nil
+}
+
 // RsaEncryptOAEP encrypts the given data with RSA-OAEP.
 // Play: https://go.dev/play/p/sSVmkfENKMz
 func RsaEncryptOAEP(data []byte, label []byte, key rsa.PublicKey) ([]byte, error) {
@@ -826,6 +1129,60 @@ func RsaDecryptOAEP(ciphertext []byte, label []byte, key rsa.PrivateKey) ([]byte
 	return decryptedBytes, nil
 }
 
+// RsaEncryptOAEPWithMGF encrypts the given data with RSA-OAEP, hash is used to digest the label and mgfHash is used by
+// the MGF1 mask generation function, eg. SHA-256 with MGF1-SHA1 which is the default of some java providers.
+func RsaEncryptOAEPWithMGF(data, label []byte, key rsa.PublicKey, hash, mgfHash crypto.Hash) ([]byte, error) {
+	if !hash.Available() || !mgfHash.Available() {
+		return nil, ErrUnsupportedHash
+	}
+
+	return rsaEncryptOAEP(rand.Reader, &key, data, label, hash, mgfHash)
+}
+
+// RsaDecryptOAEPWithMGF decrypts the data encrypted by RsaEncryptOAEPWithMGF, hash and mgfHash should be
+// the same as the ones used for encryption. Note: a mgfHash different from hash requires Go 1.20 or later.
+func RsaDecryptOAEPWithMGF(ciphertext, label []byte, key rsa.PrivateKey, hash, mgfHash crypto.Hash) ([]byte, error) {
+	if !hash.Available() || !mgfHash.Available() {
+		return nil, ErrUnsupportedHash
+	}
+
+	return rsaDecryptOAEP(&key, ciphertext, label, hash, mgfHash)
+}
+
+// RsaEncryptOAEPFromPEM encrypts the given data with RSA-OAEP, the public key is parsed from pubKeyPEM.
+func RsaEncryptOAEPFromPEM(data, label, pubKeyPEM []byte, hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrUnsupportedHash
+	}
+
+	pubKey, err := parseRsaPublicKey(pubKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsa.EncryptOAEP(hash.New(), rand.Reader, pubKey, data, label)
+}
+
+// RsaDecryptOAEPFromPEM decrypts the data with RSA-OAEP, the private key is parsed from priKeyPEM.
+// If the ciphertext or label is wrong, rsa.ErrDecryption is returned.
+func RsaDecryptOAEPFromPEM(ciphertext, label, priKeyPEM []byte, hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrUnsupportedHash
+	}
+
+	priKey, err := parseRsaPrivateKey(priKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, err := rsa.DecryptOAEP(hash.New(), rand.Reader, priKey, ciphertext, label)
+	if err != nil {
+		return nil, rsa.ErrDecryption
+	}
+
+	return decrypted, nil
+}
+
 // RsaSign signs the data with RSA.
 // Play: https://go.dev/play/p/qhsbf8BJ6Mf
 func RsaSign(hash crypto.Hash, data []byte, privateKeyFileName string) ([]byte, error) {
@@ -857,3 +1214,111 @@ func RsaVerifySign(hash crypto.Hash, data, signature []byte, pubKeyFileName stri
 
 	return rsa.VerifyPKCS1v15(publicKey, hash, hashed, signature)
 }
+
+// RsaVerifySignBase64 verifies the base64 (standard encoding) encoded signature of the data with RSA,
+// the public key is parsed from pubKeyPEM. It returns a decode error if sigB64 is not valid base64,
+// or rsa.ErrVerification if the signature is not valid.
+func RsaVerifySignBase64(hash crypto.Hash, data []byte, sigB64 string, pubKeyPEM []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return fmt.Errorf("rsa: failed to decode base64 signature: %w", err)
+	}
+
+	return rsaVerifySign(hash, data, signature, pubKeyPEM)
+}
+
+// RsaVerifySignBase64URL is like RsaVerifySignBase64, but the signature is encoded with the URL-safe base64 alphabet,
+// with or without padding, as used by JWT. The padding, if any, should be valid as in Base64URLDecode.
+func RsaVerifySignBase64URL(hash crypto.Hash, data []byte, sigB64 string, pubKeyPEM []byte) error {
+	signature, err := Base64URLDecode(sigB64)
+	if err != nil {
+		return fmt.Errorf("rsa: failed to decode base64 signature: %w", err)
+	}
+
+	return rsaVerifySign(hash, data, signature, pubKeyPEM)
+}
+
+// RsaVerifySignAny verifies the PKCS #1 v1.5 signature of the data with each of the PEM encoded public keys in order,
+// eg. the old and new keys during key rotation, and returns the index of the first key which verifies it.
+// A key which fails to parse is skipped. If no key verifies the signature, it returns -1 and an error
+// wrapping rsa.ErrVerification.
+func RsaVerifySignAny(hash crypto.Hash, data, signature []byte, pubKeyPEMs [][]byte) (int, error) {
+	if len(pubKeyPEMs) == 0 {
+		return -1, errors.New("rsa: no public key to verify the signature")
+	}
+
+	hashed, err := hashData(hash, data)
+	if err != nil {
+		return -1, err
+	}
+
+	var parseErr error
+	invalidKeys := 0
+
+	for i, pubKeyPEM := range pubKeyPEMs {
+		publicKey, err := parseRsaPublicKey(pubKeyPEM)
+		if err != nil {
+			if parseErr == nil {
+				parseErr = fmt.Errorf("key %d: %v", i, err)
+			}
+			invalidKeys++
+			continue
+		}
+
+		if rsa.VerifyPKCS1v15(publicKey, hash, hashed, signature) == nil {
+			return i, nil
+		}
+	}
+
+	if parseErr != nil {
+		return -1, fmt.Errorf("%w: none of %d public keys matched, %d failed to parse (%v)",
+			rsa.ErrVerification, len(pubKeyPEMs), invalidKeys, parseErr)
+	}
+
+	return -1, fmt.Errorf("%w: none of %d public keys matched", rsa.ErrVerification, len(pubKeyPEMs))
+}
+
+// LoadRsaPublicKeyFromCert parses the PEM encoded x509 certificate (PEM type "CERTIFICATE") and returns its rsa public key.
+// It returns an error if the public key of the certificate is not a rsa key. The certificate is not verified.
+// Note: the functions accepting pubKeyPEM, eg. RsaVerifySignBase64, also accept a certificate PEM.
+func LoadRsaPublicKeyFromCert(certPEM []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing the certificate")
+	}
+
+	if strings.ToUpper(block.Type) != "CERTIFICATE" {
+		return nil, fmt.Errorf("unexpected PEM block type %q, it should be CERTIFICATE", block.Type)
+	}
+
+	return parseRsaCertificate(block.Bytes)
+}
+
+// ConvertRsaKeyToPKCS8 converts the PEM encoded rsa private key to PKCS#8 format (PEM type "PRIVATE KEY").
+// The input can be either PKCS#1 or PKCS#8 format.
+func ConvertRsaKeyToPKCS8(pkcs1PEM []byte) ([]byte, error) {
+	privateKey, err := parseRsaPrivateKey(pkcs1PEM)
+	if err != nil {
+		return nil, fmt.Errorf("rsa: failed to convert key to PKCS#8: %w", err)
+	}
+
+	derText, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derText}), nil
+}
+
+// ConvertRsaKeyToPKCS1 converts the PEM encoded rsa private key to PKCS#1 format (PEM type "RSA PRIVATE KEY").
+// The input can be either PKCS#8 or PKCS#1 format, it returns an error if the PKCS#8 key is not a rsa key.
+func ConvertRsaKeyToPKCS1(pkcs8PEM []byte) ([]byte, error) {
+	privateKey, err := parseRsaPrivateKey(pkcs8PEM)
+	if err != nil {
+		return nil, fmt.Errorf("rsa: failed to convert key to PKCS#1: %w", err)
+	}
+
+	derText := x509.MarshalPKCS1PrivateKey(privateKey)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: derText}), nil
+}
@@ -3,36 +3,209 @@ package cryptor
 import (
 	"bytes"
 	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"os"
 	"strings"
 )
 
-func generateAesKey(key []byte, size int) []byte {
-	genKey := make([]byte, size)
-	copy(genKey, key)
-	for i := size; i < len(key); {
-		for j := 0; j < size && i < len(key); j, i = j+1, i+1 {
-			genKey[j] ^= key[i]
+// aesEncryptStream encrypts the data read from src with the stream cipher created by newStream,
+// the random IV is written to dst before the encrypted data.
+func aesEncryptStream(src io.Reader, dst io.Writer, key []byte, newStream func(block cipher.Block, iv []byte) cipher.Stream) error {
+	if !isAesKeyLengthValid(len(key)) {
+		return fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(iv); err != nil {
+		return err
+	}
+
+	writer := &cipher.StreamWriter{S: newStream(block, iv), W: dst}
+	_, err = io.Copy(writer, src)
+
+	return err
+}
+
+// aesDecryptStream reads the IV from the front of src, then decrypts the rest data with the stream cipher created by newStream.
+func aesDecryptStream(src io.Reader, dst io.Writer, key []byte, newStream func(block cipher.Block, iv []byte) cipher.Stream) error {
+	if !isAesKeyLengthValid(len(key)) {
+		return fmt.Errorf("aes: %w (must be 16, 24, or 32 bytes)", ErrInvalidKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(src, iv); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("aes: %w", ErrCiphertextTooShort)
 		}
+		return err
+	}
+
+	reader := &cipher.StreamReader{S: newStream(block, iv), R: src}
+	_, err = io.Copy(dst, reader)
+
+	return err
+}
+
+// cfb8 is the cipher.Stream of CFB mode with 8 bits segment size (CFB8), it encrypts one byte per block operation.
+type cfb8 struct {
+	block   cipher.Block
+	shift   []byte
+	out     []byte
+	decrypt bool
+}
+
+func newCFB8(block cipher.Block, iv []byte, decrypt bool) cipher.Stream {
+	shift := make([]byte, len(iv))
+	copy(shift, iv)
+
+	return &cfb8{block: block, shift: shift, out: make([]byte, block.BlockSize()), decrypt: decrypt}
+}
+
+func newCFB8Encrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, false)
+}
+
+func newCFB8Decrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, true)
+}
+
+func (c *cfb8) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("cfb8: output smaller than input")
+	}
+
+	for i, b := range src {
+		c.block.Encrypt(c.out, c.shift)
+
+		// the ciphertext byte is fed back into the shift register.
+		feedback := b
+		dst[i] = b ^ c.out[0]
+		if !c.decrypt {
+			feedback = dst[i]
+		}
+
+		copy(c.shift, c.shift[1:])
+		c.shift[len(c.shift)-1] = feedback
+	}
+}
+
+// rsaEncryptOAEP implements RSAES-OAEP encryption of RFC 8017 section 7.1.1, with distinct label hash and MGF1 hash.
+func rsaEncryptOAEP(random io.Reader, pub *rsa.PublicKey, msg, label []byte, hash, mgfHash crypto.Hash) ([]byte, error) {
+	if err := checkRsaPublicKey(pub); err != nil {
+		return nil, err
+	}
+
+	k := pub.Size()
+	hLen := hash.Size()
+
+	if len(msg) > k-2*hLen-2 {
+		return nil, rsa.ErrMessageTooLong
+	}
+
+	h := hash.New()
+	h.Write(label)
+	lHash := h.Sum(nil)
+
+	// EM = 0x00 || maskedSeed || maskedDB, DB = lHash || PS || 0x01 || M
+	em := make([]byte, k)
+	seed := em[1 : 1+hLen]
+	db := em[1+hLen:]
+
+	copy(db, lHash)
+	db[len(db)-len(msg)-1] = 0x01
+	copy(db[len(db)-len(msg):], msg)
+
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, err
 	}
-	return genKey
+
+	mgf1XOR(db, mgfHash, seed)
+	mgf1XOR(seed, mgfHash, db)
+
+	m := new(big.Int).SetBytes(em)
+	c := new(big.Int).Exp(m, big.NewInt(int64(pub.E)), pub.N)
+
+	return c.FillBytes(make([]byte, k)), nil
 }
 
-func generateDesKey(key []byte) []byte {
-	genKey := make([]byte, 8)
-	copy(genKey, key)
-	for i := 8; i < len(key); {
-		for j := 0; j < 8 && i < len(key); j, i = j+1, i+1 {
-			genKey[j] ^= key[i]
+// checkRsaPublicKey returns an error if pub is not a valid rsa public key, like the check of crypto/rsa
+// before using a public key.
+func checkRsaPublicKey(pub *rsa.PublicKey) error {
+	if pub == nil || pub.N == nil || pub.N.Sign() <= 0 {
+		return errors.New("rsa: invalid public key: missing public modulus")
+	}
+	if pub.N.Bit(0) == 0 {
+		return errors.New("rsa: invalid public key: public modulus is even")
+	}
+	if pub.E < 2 {
+		return errors.New("rsa: invalid public key: public exponent too small")
+	}
+	if pub.E > 1<<31-1 {
+		return errors.New("rsa: invalid public key: public exponent too large")
+	}
+
+	return nil
+}
+
+// mgf1XOR xors out with the MGF1 mask generated from seed with hash.
+func mgf1XOR(out []byte, hash crypto.Hash, seed []byte) {
+	h := hash.New()
+	counter := make([]byte, 4)
+	done := 0
+
+	for done < len(out) {
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter)
+		digest := h.Sum(nil)
+
+		for i := 0; i < len(digest) && done < len(out); i++ {
+			out[done] ^= digest[i]
+			done++
 		}
+
+		binary.BigEndian.PutUint32(counter, binary.BigEndian.Uint32(counter)+1)
 	}
-	return genKey
+}
+
+// checkRsaKeySize returns an error if keySize is smaller than MinRsaKeySize and weak keys are not allowed by opts.
+func checkRsaKeySize(keySize int, opts []RsaKeyOption) error {
+	config := &rsaKeyConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if keySize < MinRsaKeySize && !config.allowWeakKeys {
+		return fmt.Errorf("rsa: key size %d is too weak, it should be at least %d bits (use AllowWeakKeys option for legacy or testing)", keySize, MinRsaKeySize)
+	}
+
+	return nil
 }
 
 func pkcs7Padding(src []byte, blockSize int) []byte {
@@ -69,18 +242,24 @@ func isAesKeyLengthValid(n int) bool {
 	return n == 16 || n == 24 || n == 32
 }
 
-// loadRsaPrivateKey loads and parses a PEM encoded private key file.
+// loadRsaPublicKey loads and parses a PEM encoded public key file.
 func loadRsaPublicKey(filename string) (*rsa.PublicKey, error) {
 	pubKeyData, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
+	return parseRsaPublicKey(pubKeyData)
+}
+
+// parseRsaPublicKey parses a PEM encoded public key.
+func parseRsaPublicKey(pubKeyData []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(pubKeyData)
 	if block == nil {
 		return nil, errors.New("failed to decode PEM block containing the public key")
 	}
 
+	var err error
 	var pubKey *rsa.PublicKey
 	blockType := strings.ToUpper(block.Type)
 
@@ -110,8 +289,25 @@ func loadRsaPublicKey(filename string) (*rsa.PublicKey, error) {
 			return nil, errors.New("failed to parse RSA private key")
 		}
 
+	} else if blockType == "CERTIFICATE" {
+		return parseRsaCertificate(block.Bytes)
 	} else {
-		return nil, errors.New("unsupported key type")
+		return nil, ErrUnsupportedKeyType
+	}
+
+	return pubKey, nil
+}
+
+// parseRsaCertificate parses a DER encoded x509 certificate and returns its rsa public key.
+func parseRsaCertificate(der []byte) (*rsa.PublicKey, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: certificate public key is not a RSA key", ErrUnsupportedKeyType)
 	}
 
 	return pubKey, nil
@@ -124,11 +320,17 @@ func loadRasPrivateKey(filename string) (*rsa.PrivateKey, error) {
 		return nil, err
 	}
 
+	return parseRsaPrivateKey(priKeyData)
+}
+
+// parseRsaPrivateKey parses a PEM encoded private key.
+func parseRsaPrivateKey(priKeyData []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(priKeyData)
 	if block == nil {
 		return nil, errors.New("failed to decode PEM block containing the private key")
 	}
 
+	var err error
 	var privateKey *rsa.PrivateKey
 	blockType := strings.ToUpper(block.Type)
 
@@ -149,16 +351,31 @@ func loadRasPrivateKey(filename string) (*rsa.PrivateKey, error) {
 			return nil, errors.New("failed to parse RSA private key")
 		}
 	} else {
-		return nil, errors.New("unsupported key type")
+		return nil, ErrUnsupportedKeyType
 	}
 
 	return privateKey, nil
 }
 
+// rsaVerifySign verifies the PKCS #1 v1.5 signature of the data with the PEM encoded public key.
+func rsaVerifySign(hash crypto.Hash, data, signature, pubKeyPEM []byte) error {
+	publicKey, err := parseRsaPublicKey(pubKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := hashData(hash, data)
+	if err != nil {
+		return err
+	}
+
+	return rsa.VerifyPKCS1v15(publicKey, hash, hashed, signature)
+}
+
 // hashData returns the hash value of the data, using the specified hash function
 func hashData(hash crypto.Hash, data []byte) ([]byte, error) {
 	if !hash.Available() {
-		return nil, errors.New("unsupported hash algorithm")
+		return nil, ErrUnsupportedHash
 	}
 
 	var hashed []byte
@@ -177,7 +394,7 @@ func hashData(hash crypto.Hash, data []byte) ([]byte, error) {
 		h := sha512.Sum512(data)
 		hashed = h[:]
 	default:
-		return nil, errors.New("unsupported hash algorithm")
+		return nil, ErrUnsupportedHash
 	}
 
 	return hashed, nil
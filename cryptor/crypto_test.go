@@ -1,8 +1,26 @@
 package cryptor
 
 import (
+	"bytes"
 	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/duke-git/lancet/v2/internal"
 )
@@ -20,6 +38,80 @@ func TestAesEcbCrypt(t *testing.T) {
 	assert.Equal(data, string(aesEcbDecrypt))
 }
 
+func TestAesEcbCryptWithRawKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesEcbCryptWithRawKey")
+
+	data := []byte("0123456789abcdef")
+
+	for _, key := range []string{"abcdefghijklmnop", "abcdefghijklmnopqrstuvwx", "abcdefghijklmnopqrstuvwxyz012345"} {
+		block, err := aes.NewCipher([]byte(key))
+		assert.IsNil(err)
+
+		expected := make([]byte, aes.BlockSize)
+		block.Encrypt(expected, data)
+
+		encrypted := AesEcbEncrypt(data, []byte(key))
+		assert.Equal(expected, encrypted[:aes.BlockSize])
+
+		// a CBC ciphertext made with the same key can be decrypted by the standard library.
+		cbcEncrypted := AesCbcEncrypt(data, []byte(key))
+		decrypted := make([]byte, aes.BlockSize)
+		cipher.NewCBCDecrypter(block, cbcEncrypted[:aes.BlockSize]).CryptBlocks(decrypted, cbcEncrypted[aes.BlockSize:2*aes.BlockSize])
+		assert.Equal(data, decrypted)
+	}
+}
+
+func TestNewAesCipher(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestNewAesCipher")
+
+	key := []byte("abcdefghijklmnop")
+	data := []byte("0123456789abcdef")
+
+	block, err := NewAesCipher(key)
+	assert.IsNil(err)
+	assert.Equal(aes.BlockSize, block.BlockSize())
+
+	// the block is compatible with the crypt functions of the package.
+	encrypted := AesCbcEncrypt(data, key)
+	decrypted := make([]byte, aes.BlockSize)
+	cipher.NewCBCDecrypter(block, encrypted[:aes.BlockSize]).CryptBlocks(decrypted, encrypted[aes.BlockSize:2*aes.BlockSize])
+	assert.Equal(data, decrypted)
+
+	_, err = NewAesCipher([]byte("short key"))
+	assert.IsNotNil(err)
+}
+
+func TestDetectAesKeySize(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDetectAesKeySize")
+
+	data := []byte("hello world")
+
+	for keyLen, bits := range map[int]int{16: 128, 24: 192, 32: 256} {
+		key := bytes.Repeat([]byte("k"), keyLen)
+
+		size, err := DetectAesKeySize(key)
+		assert.IsNil(err)
+		assert.Equal(bits, size)
+
+		// every mode works with every valid key length.
+		assert.Equal(data, AesCbcDecrypt(AesCbcEncrypt(data, key), key))
+		assert.Equal(data, AesCtrCrypt(AesCtrCrypt(data, key), key))
+		assert.Equal(data, AesGcmDecrypt(AesGcmEncrypt(data, key), key))
+	}
+
+	for _, keyLen := range []int{0, 8, 15, 17, 33, 64} {
+		size, err := DetectAesKeySize(make([]byte, keyLen))
+		assert.IsNotNil(err)
+		assert.Equal(0, size)
+	}
+}
+
 func TestAesCbcCrypt(t *testing.T) {
 	t.Parallel()
 
@@ -72,6 +164,79 @@ func TestAesOfbCrypt(t *testing.T) {
 	assert.Equal(data, string(aesOfbDecrypt))
 }
 
+func TestAesCtrCryptStream(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCtrCryptStream")
+
+	data := strings.Repeat("hello world", 1000)
+	key := []byte("abcdefghijklmnop")
+
+	var encrypted bytes.Buffer
+	err := AesCtrEncryptStream(strings.NewReader(data), &encrypted, key)
+	assert.IsNil(err)
+
+	assert.Equal(data, string(AesCtrDecrypt(encrypted.Bytes(), key)))
+
+	var decrypted bytes.Buffer
+	err = AesCtrDecryptStream(bytes.NewReader(encrypted.Bytes()), &decrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted.String())
+
+	err = AesCtrDecryptStream(bytes.NewReader([]byte("short")), &decrypted, key)
+	assert.IsNotNil(err)
+}
+
+func TestAesCfbCryptStream(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCfbCryptStream")
+
+	data := strings.Repeat("hello world", 1000)
+	key := []byte("abcdefghijklmnop")
+
+	var encrypted bytes.Buffer
+	err := AesCfbEncryptStream(strings.NewReader(data), &encrypted, key)
+	assert.IsNil(err)
+
+	// compatible with the non-stream function.
+	assert.Equal(data, string(AesCfbDecrypt(encrypted.Bytes(), key)))
+
+	var decrypted bytes.Buffer
+	err = AesCfbDecryptStream(bytes.NewReader(encrypted.Bytes()), &decrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted.String())
+
+	err = AesCfbDecryptStream(strings.NewReader("short"), &decrypted, key)
+	assert.IsNotNil(err)
+
+	err = AesCfbEncryptStream(strings.NewReader(data), &encrypted, []byte("short key"))
+	assert.IsNotNil(err)
+}
+
+func TestAesOfbCryptStream(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesOfbCryptStream")
+
+	data := strings.Repeat("hello world", 1000)
+	key := []byte("abcdefghijklmnop")
+
+	var encrypted bytes.Buffer
+	err := AesOfbEncryptStream(strings.NewReader(data), &encrypted, key)
+	assert.IsNil(err)
+
+	assert.Equal(data, string(AesOfbDecrypt(encrypted.Bytes(), key)))
+
+	var decrypted bytes.Buffer
+	err = AesOfbDecryptStream(bytes.NewReader(encrypted.Bytes()), &decrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted.String())
+
+	err = AesOfbEncryptStream(iotest.ErrReader(errors.New("read failed")), &encrypted, key)
+	assert.IsNotNil(err)
+}
+
 func TestDesEcbCrypt(t *testing.T) {
 	t.Parallel()
 
@@ -152,11 +317,56 @@ func TestRsaEncrypt(t *testing.T) {
 	assert.Equal(string(data), string(decrypted))
 }
 
+func TestGenerateRsaKeyPairWithReader(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateRsaKeyPairWithReader")
+
+	pri, pub, err := GenerateRsaKeyPairWithReader(rand.Reader, 2048)
+	assert.IsNil(err)
+	assert.Equal(2048, pub.N.BitLen())
+	assert.Equal(&pri.PublicKey, pub)
+
+	_, _, err = GenerateRsaKeyPairWithReader(iotest.ErrReader(errors.New("read failed")), 2048)
+	assert.IsNotNil(err)
+}
+
+func TestGenerateRsaWeakKeys(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateRsaWeakKeys")
+
+	dir := t.TempDir()
+	priKeyFile, pubKeyFile := dir+"/rsa_private.pem", dir+"/rsa_public.pem"
+
+	err := GenerateRsaKey(1024, priKeyFile, pubKeyFile)
+	assert.IsNotNil(err)
+	assert.Equal(true, strings.Contains(err.Error(), "too weak"))
+
+	err = GenerateRsaKey(1024, priKeyFile, pubKeyFile, AllowWeakKeys())
+	assert.IsNil(err)
+
+	_, _, err = GenerateRsaKeyPairWithReader(rand.Reader, 256)
+	assert.IsNotNil(err)
+
+	_, pub, err := GenerateRsaKeyPairWithReader(rand.Reader, 1024, AllowWeakKeys())
+	assert.IsNil(err)
+	assert.Equal(1024, pub.N.BitLen())
+
+	_, pub = GenerateRsaKeyPair(1024, AllowWeakKeys())
+	assert.Equal(1024, pub.N.BitLen())
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	GenerateRsaKeyPair(1024)
+}
+
 func TestRsaEncryptOAEP(t *testing.T) {
 	assert := internal.NewAssert(t, "TestRsaEncrypt")
 	t.Parallel()
 
-	pri, pub := GenerateRsaKeyPair(1024)
+	pri, pub := GenerateRsaKeyPair(2048)
 
 	data := []byte("hello world")
 	label := []byte("123456")
@@ -170,6 +380,90 @@ func TestRsaEncryptOAEP(t *testing.T) {
 	assert.Equal("hello world", string(decrypted))
 }
 
+func TestRsaEncryptOAEPWithMGF(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaEncryptOAEPWithMGF")
+
+	pri, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+	pub := pri.PublicKey
+
+	data := []byte("hello world")
+	label := []byte("123456")
+
+	// the same hash is compatible with the standard OAEP
+	encrypted, err := RsaEncryptOAEPWithMGF(data, label, pub, crypto.SHA256, crypto.SHA256)
+	assert.IsNil(err)
+
+	decrypted, err := RsaDecryptOAEP(encrypted, label, *pri)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	_, err = RsaDecryptOAEPWithMGF(encrypted, label, *pri, crypto.SHA256, crypto.SHA1)
+	assert.IsNotNil(err)
+
+	_, err = RsaEncryptOAEPWithMGF(make([]byte, pub.Size()), label, pub, crypto.SHA256, crypto.SHA1)
+	assert.Equal(rsa.ErrMessageTooLong, err)
+
+	_, err = RsaEncryptOAEPWithMGF(data, label, pub, crypto.Hash(0), crypto.SHA1)
+	assert.IsNotNil(err)
+}
+
+func TestRsaEncryptOAEPWithMGFInvalidPublicKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaEncryptOAEPWithMGFInvalidPublicKey")
+
+	pri, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+	pub := pri.PublicKey
+
+	keys := map[string]rsa.PublicKey{
+		"missing modulus":    {E: pub.E},
+		"zero modulus":       {N: new(big.Int), E: pub.E},
+		"even modulus":       {N: new(big.Int).Add(pub.N, big.NewInt(1)), E: pub.E},
+		"exponent too small": {N: pub.N, E: 1},
+	}
+
+	for name, key := range keys {
+		_, err := RsaEncryptOAEPWithMGF([]byte("hello world"), nil, key, crypto.SHA256, crypto.SHA1)
+		if err == nil {
+			t.Errorf("expected error for the public key of %s", name)
+		}
+	}
+}
+
+func TestRsaEncryptOAEPFromPEM(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaEncryptOAEPFromPEM")
+
+	pubKeyPEM, err := os.ReadFile("./rsa_public.pem")
+	assert.IsNil(err)
+	priKeyPEM, err := os.ReadFile("./rsa_private.pem")
+	assert.IsNil(err)
+
+	data := []byte("hello world")
+	label := []byte("123456")
+
+	encrypted, err := RsaEncryptOAEPFromPEM(data, label, pubKeyPEM, crypto.SHA256)
+	assert.IsNil(err)
+
+	decrypted, err := RsaDecryptOAEPFromPEM(encrypted, label, priKeyPEM, crypto.SHA256)
+	assert.IsNil(err)
+	assert.Equal("hello world", string(decrypted))
+
+	_, err = RsaDecryptOAEPFromPEM(encrypted, []byte("654321"), priKeyPEM, crypto.SHA256)
+	assert.Equal(rsa.ErrDecryption, err)
+
+	_, err = RsaDecryptOAEPFromPEM(encrypted, label, priKeyPEM, crypto.SHA512)
+	assert.Equal(rsa.ErrDecryption, err)
+
+	_, err = RsaEncryptOAEPFromPEM(data, label, []byte("invalid pem"), crypto.SHA256)
+	assert.IsNotNil(err)
+}
+
 func TestAesGcmEncrypt(t *testing.T) {
 	t.Parallel()
 
@@ -183,6 +477,180 @@ func TestAesGcmEncrypt(t *testing.T) {
 	assert.Equal(data, string(decrypted))
 }
 
+func TestAesGcmDecryptE(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesGcmDecryptE")
+
+	data := []byte("hello world")
+	key := []byte("abcdefghijklmnop")
+
+	encrypted := AesGcmEncrypt(data, key)
+
+	decrypted, err := AesGcmDecryptE(encrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = AesGcmDecryptE(tampered, key)
+	assert.IsNotNil(err)
+
+	_, err = AesGcmDecryptE(encrypted[:5], key)
+	assert.IsNotNil(err)
+
+	_, err = AesGcmDecryptE(encrypted, []byte("short key"))
+	assert.IsNotNil(err)
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	AesGcmDecrypt(tampered, key)
+}
+
+func TestAesCbcDecryptE(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCbcDecryptE")
+
+	data := []byte("hello world")
+	key := []byte("abcdefghijklmnop")
+
+	encrypted := AesCbcEncrypt(data, key)
+
+	decrypted, err := AesCbcDecryptE(encrypted, key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	_, err = AesCbcDecryptE(encrypted, []byte("0123456789abcdef"))
+	assert.IsNotNil(err)
+
+	_, err = AesCbcDecryptE(encrypted[:16], key)
+	assert.IsNotNil(err)
+
+	_, err = AesCbcDecryptE(encrypted[:20], key)
+	assert.IsNotNil(err)
+
+	_, err = AesCbcDecryptE(encrypted, []byte("short key"))
+	assert.IsNotNil(err)
+}
+
+func TestAesCbcHmacCrypt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCbcHmacCrypt")
+
+	data := []byte("hello world")
+	encKey := []byte("abcdefghijklmnop")
+	macKey := []byte("0123456789abcdef0123456789abcdef")
+
+	encrypted, err := AesCbcHmacEncrypt(data, encKey, macKey)
+	assert.IsNil(err)
+	assert.Equal(16+16+32, len(encrypted))
+
+	decrypted, err := AesCbcHmacDecrypt(encrypted, encKey, macKey)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	// any modification of IV, ciphertext or MAC is detected
+	for _, i := range []int{0, 20, len(encrypted) - 1} {
+		tampered := append([]byte{}, encrypted...)
+		tampered[i] ^= 0x01
+		_, err = AesCbcHmacDecrypt(tampered, encKey, macKey)
+		assert.IsNotNil(err)
+	}
+
+	_, err = AesCbcHmacDecrypt(encrypted, encKey, []byte("other mac key"))
+	assert.IsNotNil(err)
+
+	_, err = AesCbcHmacDecrypt(encrypted[:10], encKey, macKey)
+	assert.IsNotNil(err)
+
+	_, err = AesCbcHmacEncrypt(data, []byte("short key"), macKey)
+	assert.IsNotNil(err)
+
+	_, err = AesCbcHmacEncrypt(data, encKey, nil)
+	assert.IsNotNil(err)
+}
+
+func TestAesCfbDecryptE(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCfbDecryptE")
+
+	data := []byte("hello world")
+	key := []byte("abcdefghijklmnop")
+
+	decrypted, err := AesCfbDecryptE(AesCfbEncrypt(data, key), key)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	_, err = AesCfbDecryptE([]byte("short"), key)
+	assert.IsNotNil(err)
+
+	_, err = AesCfbDecryptE(AesCfbEncrypt(data, key), []byte("short key"))
+	assert.IsNotNil(err)
+}
+
+func TestAesCfb8Encrypt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesCfb8Encrypt")
+
+	data := []byte("hello world")
+	key := []byte("abcdefghijklmnop")
+
+	encrypted := AesCfb8Encrypt(data, key)
+	assert.Equal(aes.BlockSize+len(data), len(encrypted))
+	assert.Equal(data, AesCfb8Decrypt(encrypted, key))
+	assert.Equal([]byte{}, AesCfb8Decrypt(AesCfb8Encrypt(nil, key), key))
+
+	// CFB8 differs from the full block CFB after the first byte.
+	iv := encrypted[:aes.BlockSize]
+	block, _ := aes.NewCipher(key)
+	cfb := make([]byte, len(data))
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(cfb, data)
+	assert.Equal(cfb[0], encrypted[aes.BlockSize])
+	assert.NotEqual(cfb, encrypted[aes.BlockSize:])
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	AesCfb8Decrypt([]byte("short"), key)
+}
+
+func TestCfb8KnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCfb8KnownAnswer")
+
+	// CFB8-AES128 test vector of NIST SP 800-38A F.3.7
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	iv, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	plaintext, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d")
+	expected := "3b79424c9c0dd436bace9e0ed4586a4f32b9"
+
+	block, _ := aes.NewCipher(key)
+
+	ciphertext := make([]byte, len(plaintext))
+	newCFB8Encrypter(block, iv).XORKeyStream(ciphertext, plaintext)
+	assert.Equal(expected, hex.EncodeToString(ciphertext))
+
+	// encrypting by pieces keeps the feedback state.
+	pieces := make([]byte, len(plaintext))
+	stream := newCFB8Encrypter(block, iv)
+	stream.XORKeyStream(pieces[:5], plaintext[:5])
+	stream.XORKeyStream(pieces[5:], plaintext[5:])
+	assert.Equal(expected, hex.EncodeToString(pieces))
+
+	decrypted := make([]byte, len(ciphertext))
+	newCFB8Decrypter(block, iv).XORKeyStream(decrypted, ciphertext)
+	assert.Equal(plaintext, decrypted)
+
+	// the iv is not modified.
+	assert.Equal("000102030405060708090a0b0c0d0e0f", hex.EncodeToString(iv))
+}
+
 func TestRsaSignAndVerify(t *testing.T) {
 	t.Parallel()
 
@@ -232,3 +700,260 @@ func TestRsaSignAndVerify(t *testing.T) {
 		}
 	})
 }
+
+func TestNewDesCipher(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestNewDesCipher")
+
+	key := []byte("abcdefgh")
+	data := []byte("01234567")
+
+	block, err := NewDesCipher(key)
+	assert.IsNil(err)
+	assert.Equal(8, block.BlockSize())
+
+	encrypted := make([]byte, 8)
+	block.Encrypt(encrypted, data)
+	assert.Equal(encrypted, DesEcbEncrypt(data, key)[:8])
+
+	_, err = NewDesCipher([]byte("abcdefghijklmnop"))
+	assert.IsNotNil(err)
+}
+
+func TestDesInvalidKeyLength(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDesInvalidKeyLength")
+
+	data := []byte("hello world")
+	validKey := []byte("abcdefgh")
+
+	funcs := map[string]func(data, key []byte) []byte{
+		"DesEcbEncrypt": DesEcbEncrypt,
+		"DesCbcEncrypt": DesCbcEncrypt,
+		"DesCtrCrypt":   DesCtrCrypt,
+		"DesCtrEncrypt": DesCtrEncrypt,
+		"DesCfbEncrypt": DesCfbEncrypt,
+		"DesOfbEncrypt": DesOfbEncrypt,
+		"DesEcbDecrypt": func(_, key []byte) []byte { return DesEcbDecrypt(DesEcbEncrypt(data, validKey), key) },
+		"DesCbcDecrypt": func(_, key []byte) []byte { return DesCbcDecrypt(DesCbcEncrypt(data, validKey), key) },
+		"DesCtrDecrypt": func(_, key []byte) []byte { return DesCtrDecrypt(DesCtrEncrypt(data, validKey), key) },
+		"DesCfbDecrypt": func(_, key []byte) []byte { return DesCfbDecrypt(DesCfbEncrypt(data, validKey), key) },
+		"DesOfbDecrypt": func(_, key []byte) []byte { return DesOfbDecrypt(DesOfbEncrypt(data, validKey), key) },
+	}
+
+	for name, fn := range funcs {
+		for _, key := range [][]byte{[]byte("abc"), []byte("abcdefghijklmnop")} {
+			func() {
+				defer func() {
+					r := recover()
+					assert.Equal("des: key length must be 8 bytes", r)
+					if r == nil {
+						t.Errorf("%s should panic with key length %d", name, len(key))
+					}
+				}()
+				fn(data, key)
+			}()
+		}
+	}
+}
+
+func TestRsaVerifySignBase64(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaVerifySignBase64")
+
+	pubKeyPEM, err := os.ReadFile("./rsa_public.pem")
+	assert.IsNil(err)
+
+	data := []byte("This is a test data for RSA signing")
+
+	signature, err := RsaSign(crypto.SHA256, data, "./rsa_private.pem")
+	assert.IsNil(err)
+
+	err = RsaVerifySignBase64(crypto.SHA256, data, base64.StdEncoding.EncodeToString(signature), pubKeyPEM)
+	assert.IsNil(err)
+
+	err = RsaVerifySignBase64(crypto.SHA256, []byte("other data"), base64.StdEncoding.EncodeToString(signature), pubKeyPEM)
+	assert.Equal(rsa.ErrVerification, err)
+
+	err = RsaVerifySignBase64(crypto.SHA256, data, "invalid base64!", pubKeyPEM)
+	assert.IsNotNil(err)
+	assert.NotEqual(rsa.ErrVerification, err)
+
+	var corruptInputError base64.CorruptInputError
+	assert.Equal(true, errors.As(err, &corruptInputError))
+
+	err = RsaVerifySignBase64URL(crypto.SHA256, data, base64.RawURLEncoding.EncodeToString(signature), pubKeyPEM)
+	assert.IsNil(err)
+
+	err = RsaVerifySignBase64URL(crypto.SHA256, data, base64.URLEncoding.EncodeToString(signature), pubKeyPEM)
+	assert.IsNil(err)
+
+	// the padding is as strict as Base64URLDecode.
+	err = RsaVerifySignBase64URL(crypto.SHA256, data, base64.URLEncoding.EncodeToString(signature)+"=", pubKeyPEM)
+	assert.Equal(true, errors.As(err, &corruptInputError))
+
+	err = RsaVerifySignBase64URL(crypto.SHA256, data, base64.URLEncoding.EncodeToString(signature)+"==", pubKeyPEM)
+	assert.Equal(true, errors.As(err, &corruptInputError))
+}
+
+func TestRsaVerifySignAny(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaVerifySignAny")
+
+	oldKeyPEM, err := os.ReadFile("./rsa_public_example.pem")
+	assert.IsNil(err)
+	newKeyPEM, err := os.ReadFile("./rsa_public.pem")
+	assert.IsNil(err)
+
+	data := []byte("This is a test data for RSA signing")
+
+	signature, err := RsaSign(crypto.SHA256, data, "./rsa_private.pem")
+	assert.IsNil(err)
+
+	index, err := RsaVerifySignAny(crypto.SHA256, data, signature, [][]byte{oldKeyPEM, newKeyPEM})
+	assert.IsNil(err)
+	assert.Equal(1, index)
+
+	// an invalid key doesn't stop trying the remaining keys.
+	index, err = RsaVerifySignAny(crypto.SHA256, data, signature, [][]byte{[]byte("invalid"), oldKeyPEM, newKeyPEM})
+	assert.IsNil(err)
+	assert.Equal(2, index)
+
+	index, err = RsaVerifySignAny(crypto.SHA256, []byte("other data"), signature, [][]byte{oldKeyPEM, newKeyPEM})
+	assert.Equal(-1, index)
+	assert.Equal(true, errors.Is(err, rsa.ErrVerification))
+
+	index, err = RsaVerifySignAny(crypto.SHA256, data, signature, [][]byte{oldKeyPEM, []byte("invalid")})
+	assert.Equal(-1, index)
+	assert.Equal(true, errors.Is(err, rsa.ErrVerification))
+	assert.Equal(true, strings.Contains(err.Error(), "1 failed to parse"))
+
+	index, err = RsaVerifySignAny(crypto.SHA256, data, signature, nil)
+	assert.Equal(-1, index)
+	assert.IsNotNil(err)
+}
+
+func TestConvertRsaKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestConvertRsaKey")
+
+	pkcs1PEM, err := os.ReadFile("./rsa_private.pem")
+	assert.IsNil(err)
+
+	expected, err := parseRsaPrivateKey(pkcs1PEM)
+	assert.IsNil(err)
+
+	pkcs8PEM, err := ConvertRsaKeyToPKCS8(pkcs1PEM)
+	assert.IsNil(err)
+
+	block, _ := pem.Decode(pkcs8PEM)
+	assert.Equal("PRIVATE KEY", block.Type)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.IsNil(err)
+	assert.Equal(true, expected.Equal(key))
+
+	converted, err := ConvertRsaKeyToPKCS1(pkcs8PEM)
+	assert.IsNil(err)
+
+	block, _ = pem.Decode(converted)
+	assert.Equal("RSA PRIVATE KEY", block.Type)
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	assert.IsNil(err)
+	assert.Equal(true, expected.Equal(privateKey))
+
+	// not a rsa key
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	derText, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	ecPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derText})
+
+	_, err = ConvertRsaKeyToPKCS1(ecPEM)
+	assert.IsNotNil(err)
+
+	_, err = ConvertRsaKeyToPKCS8([]byte("invalid pem"))
+	assert.IsNotNil(err)
+}
+
+func TestRsaEncryptBase64(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaEncryptBase64")
+
+	pubKeyPEM, err := os.ReadFile("./rsa_public.pem")
+	assert.IsNil(err)
+	priKeyPEM, err := os.ReadFile("./rsa_private.pem")
+	assert.IsNil(err)
+
+	encoded, err := RsaEncryptBase64("hello world", pubKeyPEM)
+	assert.IsNil(err)
+
+	decrypted, err := RsaDecryptBase64(encoded, priKeyPEM)
+	assert.IsNil(err)
+	assert.Equal("hello world", decrypted)
+
+	var corruptErr base64.CorruptInputError
+	_, err = RsaDecryptBase64("not base64!", priKeyPEM)
+	assert.Equal(true, errors.As(err, &corruptErr))
+
+	_, err = RsaDecryptBase64(base64.StdEncoding.EncodeToString([]byte("invalid ciphertext")), priKeyPEM)
+	assert.Equal(true, errors.Is(err, rsa.ErrDecryption))
+
+	_, err = RsaEncryptBase64("hello world", []byte("invalid pem"))
+	assert.IsNotNil(err)
+}
+
+func TestLoadRsaPublicKeyFromCert(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestLoadRsaPublicKeyFromCert")
+
+	priKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	certPEM := createTestCertificate(t, priKey, &priKey.PublicKey)
+
+	pubKey, err := LoadRsaPublicKeyFromCert(certPEM)
+	assert.IsNil(err)
+	assert.Equal(true, priKey.PublicKey.Equal(pubKey))
+
+	// verify signature against the certificate directly
+	data := []byte("hello world")
+	signature, err := RsaSign(crypto.SHA256, data, "./rsa_private.pem")
+	assert.IsNil(err)
+
+	err = RsaVerifySignBase64(crypto.SHA256, data, base64.StdEncoding.EncodeToString(signature), certPEM)
+	assert.IsNil(err)
+
+	// not a rsa certificate
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, err = LoadRsaPublicKeyFromCert(createTestCertificate(t, ecKey, &ecKey.PublicKey))
+	assert.IsNotNil(err)
+
+	pubKeyPEM, _ := os.ReadFile("./rsa_public.pem")
+	_, err = LoadRsaPublicKeyFromCert(pubKeyPEM)
+	assert.IsNotNil(err)
+
+	_, err = LoadRsaPublicKeyFromCert([]byte("invalid pem"))
+	assert.IsNotNil(err)
+}
+
+// createTestCertificate creates a PEM encoded self-signed certificate.
+func createTestCertificate(t *testing.T, priKey crypto.Signer, pubKey any) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "lancet"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pubKey, priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
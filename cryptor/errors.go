@@ -0,0 +1,27 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import "errors"
+
+// The sentinel errors of the package. The errors returned by the functions of the package wrap them when applicable,
+// so they can be checked by errors.Is, eg. errors.Is(err, ErrDecryptionFailed).
+// Note: the values of the panics raised by the panicking functions are plain strings, not these errors.
+var (
+	// ErrInvalidKeySize is returned if the length of a key is not valid for the algorithm.
+	ErrInvalidKeySize = errors.New("invalid key size")
+
+	// ErrCiphertextTooShort is returned if a ciphertext is shorter than its IV, nonce or header.
+	ErrCiphertextTooShort = errors.New("ciphertext too short")
+
+	// ErrUnsupportedHash is returned if a hash algorithm is not supported or not linked into the binary.
+	ErrUnsupportedHash = errors.New("unsupported hash algorithm")
+
+	// ErrUnsupportedKeyType is returned if a parsed key is not of the expected type, eg. not a RSA key.
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+
+	// ErrDecryptionFailed is returned if a ciphertext fails authentication or has invalid padding,
+	// eg. it's tampered or decrypted with a wrong key.
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
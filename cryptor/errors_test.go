@@ -0,0 +1,66 @@
+package cryptor
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSentinelErrors")
+
+	key := []byte("abcdefghijklmnop")
+	shortKey := []byte("short")
+
+	_, err := NewAesCipher(shortKey)
+	assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+
+	_, err = NewDesCipher(shortKey)
+	assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+
+	_, err = NewChacha20Poly1305(shortKey)
+	assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+
+	_, err = GenerateAesKey(100)
+	assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+
+	_, err = AesGcmDecryptE([]byte("short"), key)
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+
+	_, err = AesCbcDecryptE([]byte("short"), key)
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+
+	_, err = AesCfbDecryptE([]byte("short"), key)
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+
+	tampered := AesGcmEncrypt([]byte("hello"), key)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = AesGcmDecryptE(tampered, key)
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = AesCbcDecryptE(append(AesCbcEncrypt([]byte("hello"), key), 0), key)
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	macKey := []byte("mac key")
+	sealed, err := AesCbcHmacEncrypt([]byte("hello"), key, macKey)
+	assert.IsNil(err)
+	_, err = AesCbcHmacDecrypt(sealed, key, []byte("other mac key"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = HkdfKey([]byte("secret"), nil, nil, 32, crypto.MD5)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedHash))
+
+	_, err = RsaSign(crypto.Hash(0), []byte("hello"), "./rsa_private.pem")
+	assert.Equal(true, errors.Is(err, ErrUnsupportedHash))
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, err = LoadRsaPublicKeyFromCert(createTestCertificate(t, ecKey, &ecKey.PublicKey))
+	assert.Equal(true, errors.Is(err, ErrUnsupportedKeyType))
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// fileMagic is the header of the file encrypted by EncryptFile, it is followed by the IV and the encrypted content.
+var fileMagic = []byte("LCTAES01")
+
+// EncryptFile encrypts the content of srcPath with key use AES CTR algorithm and writes the result to dstPath.
+// The encrypted file starts with a magic header and the random IV. len(key) should be 16, 24 or 32.
+// It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+// Note: AES CTR doesn't authenticate the content, a tampered file can't be detected on decryption.
+func EncryptFile(srcPath, dstPath string, key []byte, overwrite bool) error {
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		if _, err := dst.Write(fileMagic); err != nil {
+			return err
+		}
+
+		return AesCtrEncryptStream(src, dst, key)
+	})
+}
+
+// DecryptFile decrypts the file encrypted by EncryptFile with key and writes the result to dstPath.
+// It returns an error if srcPath doesn't start with the magic header.
+// It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+func DecryptFile(srcPath, dstPath string, key []byte, overwrite bool) error {
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		magic := make([]byte, len(fileMagic))
+		if _, err := io.ReadFull(src, magic); err != nil || !bytes.Equal(magic, fileMagic) {
+			return errors.New("cryptor: invalid encrypted file header")
+		}
+
+		return AesCtrDecryptStream(src, dst, key)
+	})
+}
+
+// processFile writes the data processed by fn from srcPath to a temporary file, then renames it to dstPath,
+// so that dstPath is never left partially written and srcPath can be the same as dstPath.
+func processFile(srcPath, dstPath string, overwrite bool, fn func(src io.Reader, dst io.Writer) error) error {
+	if !overwrite {
+		if _, err := os.Stat(dstPath); err == nil {
+			return fmt.Errorf("cryptor: destination file %s already exists: %w", dstPath, os.ErrExist)
+		}
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dstPath), "."+filepath.Base(dstPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := fn(src, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dstPath)
+}
+
+const (
+	// gcmFileChunkSize is the size of plaintext sealed in each chunk of the file encrypted by EncryptFileGCM.
+	gcmFileChunkSize = 64 * 1024
+
+	gcmFileLastChunk = 1
+)
+
+// gcmFileMagic is the header of the file encrypted by EncryptFileGCM, it is followed by the base nonce and the sealed chunks.
+var gcmFileMagic = []byte("LCTGCM01")
+
+// EncryptFileGCM encrypts the content of srcPath with key use AES GCM algorithm and writes the result to dstPath.
+// The content is split into chunks of 64KB, each chunk is sealed with a nonce derived from a random base nonce
+// and the chunk index, and written with a 4 bytes big endian length prefix. The memory used is bounded by the chunk size.
+// len(key) should be 16, 24 or 32. It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+func EncryptFileGCM(srcPath, dstPath string, key []byte, overwrite bool) error {
+	gcm, err := NewAesGcm(key)
+	if err != nil {
+		return err
+	}
+
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		return sealFileChunks(src, dst, gcm, gcmFileMagic)
+	})
+}
+
+// DecryptFileGCM decrypts the file encrypted by EncryptFileGCM with key and writes the result to dstPath.
+// Every chunk is authenticated, it returns an error and leaves dstPath untouched if the file is modified,
+// truncated or its chunks are reordered. It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+func DecryptFileGCM(srcPath, dstPath string, key []byte, overwrite bool) error {
+	gcm, err := NewAesGcm(key)
+	if err != nil {
+		return err
+	}
+
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		magic := make([]byte, len(gcmFileMagic))
+		if _, err := io.ReadFull(src, magic); err != nil || !bytes.Equal(magic, gcmFileMagic) {
+			return errors.New("cryptor: invalid encrypted file header")
+		}
+
+		return openFileChunks(src, dst, gcm, gcmFileMagic)
+	})
+}
+
+const (
+	passwordFileVersion = 1
+	passwordFileKdf     = 1 // Argon2id
+
+	// the limits of the kdf parameters accepted on decryption, the kdf runs before the file is authenticated,
+	// so a forged header could otherwise make it run for long or allocate much memory.
+	passwordFileMaxTime   = 10
+	passwordFileMaxMemory = 1024 * 1024 // 1 GiB in KiB
+)
+
+// passwordFileMagic is the header of the file encrypted by EncryptFileWithPassword.
+var passwordFileMagic = []byte("LCTPWD")
+
+// EncryptFileWithPassword encrypts the content of srcPath with password and writes the result to dstPath,
+// the file can be decrypted by DecryptFileWithPassword on any machine knowing only the password.
+// The key is derived from password and a random salt with Argon2id, and the content is encrypted with AES-256-GCM
+// in chunks like EncryptFileGCM. The format of the file (version 1) is:
+// magic "LCTPWD" | version (1 byte) | kdf id (1 byte, 1 is Argon2id) | time (4 bytes) | memory in KiB (4 bytes) |
+// threads (1 byte) | salt length (1 byte) | salt | base nonce (12 bytes) | chunks, the integers are big endian.
+// The header before the base nonce is authenticated with every chunk.
+// Decryption rejects the files of time above 10 or memory above 1 GiB before running the kdf.
+// It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+func EncryptFileWithPassword(srcPath, dstPath, password string, overwrite bool) error {
+	salt, err := GenerateRandomBytes(passwordSaltSize)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := NewAesGcm(argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen))
+	if err != nil {
+		return err
+	}
+
+	params := make([]byte, 12)
+	params[0], params[1] = passwordFileVersion, passwordFileKdf
+	binary.BigEndian.PutUint32(params[2:], argon2Time)
+	binary.BigEndian.PutUint32(params[6:], argon2Memory)
+	params[10], params[11] = argon2Threads, byte(len(salt))
+
+	header := append(append(append([]byte{}, passwordFileMagic...), params...), salt...)
+
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		return sealFileChunks(src, dst, gcm, header)
+	})
+}
+
+// DecryptFileWithPassword decrypts the file encrypted by EncryptFileWithPassword with password and writes the result to dstPath.
+// The kdf parameters and salt are read from the file header. It returns an error and leaves dstPath untouched
+// if the password is wrong or the file is modified or truncated.
+// It returns an error wrapping os.ErrExist if dstPath already exists and overwrite is false.
+func DecryptFileWithPassword(srcPath, dstPath, password string, overwrite bool) error {
+	return processFile(srcPath, dstPath, overwrite, func(src io.Reader, dst io.Writer) error {
+		header := make([]byte, len(passwordFileMagic)+12)
+		if _, err := io.ReadFull(src, header); err != nil || !bytes.Equal(header[:len(passwordFileMagic)], passwordFileMagic) {
+			return errors.New("cryptor: invalid encrypted file header")
+		}
+
+		params := header[len(passwordFileMagic):]
+		if params[0] != passwordFileVersion {
+			return fmt.Errorf("cryptor: unsupported encrypted file version %d", params[0])
+		}
+		if params[1] != passwordFileKdf {
+			return fmt.Errorf("cryptor: unsupported key derivation function %d", params[1])
+		}
+
+		iterations, memory, threads := binary.BigEndian.Uint32(params[2:]), binary.BigEndian.Uint32(params[6:]), params[10]
+		if iterations == 0 || iterations > passwordFileMaxTime || threads == 0 || memory < 8*uint32(threads) || memory > passwordFileMaxMemory {
+			return errors.New("cryptor: invalid key derivation parameters")
+		}
+
+		salt := make([]byte, params[11])
+		if _, err := io.ReadFull(src, salt); err != nil || len(salt) == 0 {
+			return errors.New("cryptor: invalid encrypted file header")
+		}
+
+		gcm, err := NewAesGcm(argon2.IDKey([]byte(password), salt, iterations, memory, threads, argon2KeyLen))
+		if err != nil {
+			return err
+		}
+
+		return openFileChunks(src, dst, gcm, append(header, salt...))
+	})
+}
+
+// sealFileChunks writes header and a random base nonce to dst, followed by the chunks of src sealed with gcm.
+// The chunk layout is: sealed length (4 bytes) | last chunk flag (1 byte) | sealed data,
+// the header and the flag are authenticated as the additional data of every chunk.
+func sealFileChunks(src io.Reader, dst io.Writer, gcm AEAD, header []byte) error {
+	baseNonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, baseNonce); err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(append(append([]byte{}, header...), baseNonce...)); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(src, gcmFileChunkSize)
+	chunk := make([]byte, gcmFileChunkSize)
+
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(reader, chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		last := err != nil
+		if !last {
+			if _, err := reader.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+
+		flag := byte(0)
+		if last {
+			flag = gcmFileLastChunk
+		}
+
+		sealed := gcm.Seal(gcmChunkNonce(baseNonce, index), chunk[:n], gcmChunkAad(header, flag))
+
+		prefix := make([]byte, 5)
+		binary.BigEndian.PutUint32(prefix, uint32(len(sealed)))
+		prefix[4] = flag
+		if _, err := dst.Write(append(prefix, sealed...)); err != nil {
+			return err
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
+
+// openFileChunks reads the base nonce from src, then opens the chunks written by sealFileChunks with gcm and the same header,
+// and writes the plaintext to dst.
+func openFileChunks(src io.Reader, dst io.Writer, gcm AEAD, header []byte) error {
+	baseNonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(src, baseNonce); err != nil {
+		return errors.New("cryptor: invalid encrypted file header")
+	}
+
+	reader := bufio.NewReader(src)
+	sealed := make([]byte, gcmFileChunkSize+gcm.Overhead())
+	prefix := make([]byte, 5)
+
+	for index := uint64(0); ; index++ {
+		if _, err := io.ReadFull(reader, prefix); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return fmt.Errorf("cryptor: %w: encrypted file is truncated", ErrCiphertextTooShort)
+			}
+			return err
+		}
+
+		n := binary.BigEndian.Uint32(prefix)
+		if n > uint32(len(sealed)) {
+			return errors.New("cryptor: invalid encrypted chunk length")
+		}
+
+		if _, err := io.ReadFull(reader, sealed[:n]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return fmt.Errorf("cryptor: %w: encrypted file is truncated", ErrCiphertextTooShort)
+			}
+			return err
+		}
+
+		// the flag is authenticated as additional data, so the file can't be truncated by forging it.
+		plaintext, err := gcm.Open(gcmChunkNonce(baseNonce, index), sealed[:n], gcmChunkAad(header, prefix[4]))
+		if err != nil {
+			return fmt.Errorf("cryptor: %w: encrypted file is corrupted", ErrDecryptionFailed)
+		}
+
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+
+		if prefix[4] == gcmFileLastChunk {
+			if _, err := reader.Peek(1); err == nil {
+				return errors.New("cryptor: unexpected data after the last chunk")
+			} else if err != io.EOF {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// gcmChunkAad returns the additional data of a chunk, that is the file header followed by the last chunk flag.
+func gcmChunkAad(header []byte, flag byte) []byte {
+	return append(append([]byte{}, header...), flag)
+}
+
+// gcmChunkNonce returns the nonce of the chunk at index, that is the base nonce xor the big endian index at its tail.
+func gcmChunkNonce(baseNonce []byte, index uint64) []byte {
+	nonce := append([]byte{}, baseNonce...)
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, index)
+
+	offset := len(nonce) - len(counter)
+	for i, b := range counter {
+		nonce[offset+i] ^= b
+	}
+
+	return nonce
+}
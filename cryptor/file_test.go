@@ -0,0 +1,325 @@
+package cryptor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestEncryptFile(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEncryptFile")
+
+	dir := t.TempDir()
+	key := []byte("abcdefghijklmnop")
+	data := strings.Repeat("hello world", 1000)
+
+	plainFile := filepath.Join(dir, "plain.txt")
+	encryptedFile := filepath.Join(dir, "plain.txt.enc")
+	decryptedFile := filepath.Join(dir, "decrypted.txt")
+
+	err := os.WriteFile(plainFile, []byte(data), 0644)
+	assert.IsNil(err)
+
+	err = EncryptFile(plainFile, encryptedFile, key, false)
+	assert.IsNil(err)
+
+	encrypted, _ := os.ReadFile(encryptedFile)
+	assert.Equal(len(fileMagic)+16+len(data), len(encrypted))
+	assert.Equal(string(fileMagic), string(encrypted[:len(fileMagic)]))
+
+	err = DecryptFile(encryptedFile, decryptedFile, key, false)
+	assert.IsNil(err)
+
+	decrypted, _ := os.ReadFile(decryptedFile)
+	assert.Equal(data, string(decrypted))
+
+	// destination exists
+	err = EncryptFile(plainFile, encryptedFile, key, false)
+	assert.Equal(true, errors.Is(err, os.ErrExist))
+
+	err = EncryptFile(plainFile, encryptedFile, key, true)
+	assert.IsNil(err)
+
+	// encrypt in place
+	err = EncryptFile(plainFile, plainFile, key, true)
+	assert.IsNil(err)
+	err = DecryptFile(plainFile, plainFile, key, true)
+	assert.IsNil(err)
+	decrypted, _ = os.ReadFile(plainFile)
+	assert.Equal(data, string(decrypted))
+}
+
+func TestDecryptFileInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDecryptFileInvalid")
+
+	dir := t.TempDir()
+	key := []byte("abcdefghijklmnop")
+
+	plainFile := filepath.Join(dir, "plain.txt")
+	err := os.WriteFile(plainFile, []byte("hello world"), 0644)
+	assert.IsNil(err)
+
+	dstFile := filepath.Join(dir, "out.txt")
+
+	err = DecryptFile(plainFile, dstFile, key, false)
+	assert.IsNotNil(err)
+
+	_, err = os.Stat(dstFile)
+	assert.Equal(true, os.IsNotExist(err))
+
+	err = EncryptFile(filepath.Join(dir, "missing.txt"), dstFile, key, false)
+	assert.IsNotNil(err)
+
+	err = EncryptFile(plainFile, dstFile, []byte("short key"), false)
+	assert.IsNotNil(err)
+
+	entries, _ := os.ReadDir(dir)
+	assert.Equal(1, len(entries))
+}
+
+func TestEncryptFileGCM(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEncryptFileGCM")
+
+	dir := t.TempDir()
+	key := []byte("abcdefghijklmnop")
+
+	for _, size := range []int{0, 10, gcmFileChunkSize, gcmFileChunkSize*2 + 100} {
+		data := strings.Repeat("a", size)
+
+		plainFile := filepath.Join(dir, "plain.txt")
+		encryptedFile := filepath.Join(dir, "plain.txt.enc")
+		decryptedFile := filepath.Join(dir, "decrypted.txt")
+
+		err := os.WriteFile(plainFile, []byte(data), 0644)
+		assert.IsNil(err)
+
+		err = EncryptFileGCM(plainFile, encryptedFile, key, true)
+		assert.IsNil(err)
+
+		err = DecryptFileGCM(encryptedFile, decryptedFile, key, true)
+		assert.IsNil(err)
+
+		decrypted, _ := os.ReadFile(decryptedFile)
+		assert.Equal(data, string(decrypted))
+	}
+
+	plainFile := filepath.Join(dir, "plain.txt")
+	err := EncryptFileGCM(plainFile, plainFile, key, false)
+	assert.Equal(true, errors.Is(err, os.ErrExist))
+
+	err = EncryptFileGCM(plainFile, filepath.Join(dir, "out.enc"), []byte("short key"), false)
+	assert.IsNotNil(err)
+}
+
+func TestDecryptFileGCMTampered(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDecryptFileGCMTampered")
+
+	dir := t.TempDir()
+	key := []byte("abcdefghijklmnop")
+
+	plainFile := filepath.Join(dir, "plain.txt")
+	encryptedFile := filepath.Join(dir, "plain.txt.enc")
+	tamperedFile := filepath.Join(dir, "tampered.enc")
+	decryptedFile := filepath.Join(dir, "decrypted.txt")
+
+	// 3 chunks, the last one is partial.
+	err := os.WriteFile(plainFile, []byte(strings.Repeat("abc", gcmFileChunkSize)), 0644)
+	assert.IsNil(err)
+
+	err = EncryptFileGCM(plainFile, encryptedFile, key, false)
+	assert.IsNil(err)
+
+	encrypted, _ := os.ReadFile(encryptedFile)
+
+	headerSize := len(gcmFileMagic) + 12
+	chunkSize := 5 + gcmFileChunkSize + 16
+	firstChunk := encrypted[headerSize : headerSize+chunkSize]
+	secondChunk := encrypted[headerSize+chunkSize : headerSize+2*chunkSize]
+
+	reordered := append([]byte{}, encrypted[:headerSize]...)
+	reordered = append(reordered, secondChunk...)
+	reordered = append(reordered, firstChunk...)
+	reordered = append(reordered, encrypted[headerSize+2*chunkSize:]...)
+
+	modified := append([]byte{}, encrypted...)
+	modified[headerSize+100] ^= 0xff
+
+	forgedLast := append([]byte{}, encrypted[:headerSize+chunkSize]...)
+	forgedLast[headerSize+4] = gcmFileLastChunk
+
+	cases := map[string][]byte{
+		"modified":         modified,
+		"reordered":        reordered,
+		"truncated chunk":  encrypted[:len(encrypted)-1],
+		"dropped chunk":    encrypted[:headerSize+2*chunkSize],
+		"forged last flag": forgedLast,
+		"trailing data":    append(append([]byte{}, encrypted...), 0),
+		"invalid header":   []byte("hello world"),
+	}
+
+	for name, data := range cases {
+		err := os.WriteFile(tamperedFile, data, 0644)
+		assert.IsNil(err)
+
+		err = DecryptFileGCM(tamperedFile, decryptedFile, key, true)
+		if err == nil {
+			t.Errorf("expected error for %s file", name)
+		}
+	}
+
+	_, err = os.Stat(decryptedFile)
+	assert.Equal(true, os.IsNotExist(err))
+
+	err = DecryptFileGCM(encryptedFile, decryptedFile, []byte("0123456789abcdef"), false)
+	assert.IsNotNil(err)
+}
+
+func TestEncryptFileWithPassword(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEncryptFileWithPassword")
+
+	dir := t.TempDir()
+	password := "correct horse battery staple"
+
+	plainFile := filepath.Join(dir, "plain.txt")
+	encryptedFile := filepath.Join(dir, "plain.txt.enc")
+	tamperedFile := filepath.Join(dir, "tampered.enc")
+	decryptedFile := filepath.Join(dir, "decrypted.txt")
+
+	data := strings.Repeat("abc", gcmFileChunkSize)
+	err := os.WriteFile(plainFile, []byte(data), 0644)
+	assert.IsNil(err)
+
+	err = EncryptFileWithPassword(plainFile, encryptedFile, password, false)
+	assert.IsNil(err)
+
+	err = DecryptFileWithPassword(encryptedFile, decryptedFile, password, false)
+	assert.IsNil(err)
+
+	decrypted, _ := os.ReadFile(decryptedFile)
+	assert.Equal(data, string(decrypted))
+
+	err = EncryptFileWithPassword(plainFile, encryptedFile, password, false)
+	assert.Equal(true, errors.Is(err, os.ErrExist))
+
+	encrypted, _ := os.ReadFile(encryptedFile)
+	assert.Equal(passwordFileMagic, encrypted[:len(passwordFileMagic)])
+
+	params := len(passwordFileMagic)
+
+	unknownVersion := append([]byte{}, encrypted...)
+	unknownVersion[params] = 2
+
+	unknownKdf := append([]byte{}, encrypted...)
+	unknownKdf[params+1] = 2
+
+	hugeMemory := append([]byte{}, encrypted...)
+	hugeMemory[params+6] = 0xff
+
+	modifiedSalt := append([]byte{}, encrypted...)
+	modifiedSalt[params+12] ^= 0xff
+
+	modified := append([]byte{}, encrypted...)
+	modified[len(modified)-1] ^= 0xff
+
+	cases := map[string][]byte{
+		"unknown version": unknownVersion,
+		"unknown kdf":     unknownKdf,
+		"huge memory":     hugeMemory,
+		"modified salt":   modifiedSalt,
+		"modified":        modified,
+		"truncated":       encrypted[:len(encrypted)-1],
+		"invalid header":  []byte("hello world"),
+	}
+
+	for name, data := range cases {
+		err := os.WriteFile(tamperedFile, data, 0644)
+		assert.IsNil(err)
+
+		err = DecryptFileWithPassword(tamperedFile, filepath.Join(dir, "out.txt"), password, true)
+		if err == nil {
+			t.Errorf("expected error for %s file", name)
+		}
+	}
+
+	err = DecryptFileWithPassword(encryptedFile, filepath.Join(dir, "out.txt"), "wrong password", true)
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = os.Stat(filepath.Join(dir, "out.txt"))
+	assert.Equal(true, os.IsNotExist(err))
+}
+
+func TestDecryptFileWithPasswordForgedHeader(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDecryptFileWithPasswordForgedHeader")
+
+	dir := t.TempDir()
+	plainFile := filepath.Join(dir, "plain.txt")
+	encryptedFile := filepath.Join(dir, "plain.txt.enc")
+	forgedFile := filepath.Join(dir, "forged.enc")
+
+	err := os.WriteFile(plainFile, []byte("hello world"), 0644)
+	assert.IsNil(err)
+
+	err = EncryptFileWithPassword(plainFile, encryptedFile, "password", false)
+	assert.IsNil(err)
+
+	encrypted, _ := os.ReadFile(encryptedFile)
+	params := len(passwordFileMagic)
+
+	// the oversized parameters are rejected before running the kdf, instead of failing the authentication.
+	forgedTime := append([]byte{}, encrypted...)
+	binary.BigEndian.PutUint32(forgedTime[params+2:], passwordFileMaxTime+1)
+
+	forgedMemory := append([]byte{}, encrypted...)
+	binary.BigEndian.PutUint32(forgedMemory[params+6:], passwordFileMaxMemory+1)
+
+	for _, forged := range [][]byte{forgedTime, forgedMemory} {
+		err = os.WriteFile(forgedFile, forged, 0644)
+		assert.IsNil(err)
+
+		err = DecryptFileWithPassword(forgedFile, filepath.Join(dir, "out.txt"), "password", true)
+		assert.Equal("cryptor: invalid key derivation parameters", err.Error())
+	}
+}
+
+func TestFileChunksAuthenticateHeader(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFileChunksAuthenticateHeader")
+
+	gcm, err := NewAesGcm([]byte("0123456789abcdef"))
+	assert.IsNil(err)
+
+	header := []byte("header")
+
+	var sealed bytes.Buffer
+	err = sealFileChunks(strings.NewReader("hello world"), &sealed, gcm, header)
+	assert.IsNil(err)
+
+	body := sealed.Bytes()[len(header):]
+
+	var opened bytes.Buffer
+	err = openFileChunks(bytes.NewReader(body), &opened, gcm, header)
+	assert.IsNil(err)
+	assert.Equal("hello world", opened.String())
+
+	err = openFileChunks(bytes.NewReader(body), &bytes.Buffer{}, gcm, []byte("HEADER"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+}
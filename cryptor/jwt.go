@@ -0,0 +1,218 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The algorithms supported by SignJWT and VerifyJWT.
+const (
+	JwtHS256 = "HS256"
+	JwtRS256 = "RS256"
+)
+
+// The errors returned by VerifyJWT, they can be checked by errors.Is.
+var (
+	// ErrJWTMalformed is returned if the token is not a well-formed JWS compact serialization of a JSON object.
+	ErrJWTMalformed = errors.New("jwt: malformed token")
+
+	// ErrJWTInvalidSignature is returned if the signature doesn't match, or the algorithm doesn't match the key.
+	ErrJWTInvalidSignature = errors.New("jwt: invalid signature")
+
+	// ErrJWTExpired is returned if the token is expired by the exp claim.
+	ErrJWTExpired = errors.New("jwt: token is expired")
+
+	// ErrJWTNotValidYet is returned if the token is not valid yet by the nbf claim.
+	ErrJWTNotValidYet = errors.New("jwt: token is not valid yet")
+)
+
+// SignJWT returns the JWT (RFC 7519) of claims signed with key, alg should be JwtHS256 or JwtRS256.
+// For JwtHS256, key should be the hmac secret of type []byte or string. For JwtRS256, key should be a *rsa.PrivateKey.
+// The exp and nbf claims, if any, should be unix timestamps in seconds. claims should not be nil.
+func SignJWT(claims map[string]any, key any, alg string) (string, error) {
+	if claims == nil {
+		return "", errors.New("jwt: claims should not be nil")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt: failed to encode claims: %w", err)
+	}
+
+	signingInput := Base64URLEncode(header) + "." + Base64URLEncode(payload)
+
+	var signature []byte
+
+	switch alg {
+	case JwtHS256:
+		secret, err := jwtHmacSecret(key)
+		if err != nil {
+			return "", err
+		}
+		signature = jwtHmacSign(secret, signingInput)
+	case JwtRS256:
+		privateKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("jwt: %w: RS256 key should be *rsa.PrivateKey, got %T", ErrUnsupportedKeyType, key)
+		}
+
+		hashed := sha256.Sum256([]byte(signingInput))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("jwt: unsupported algorithm %q", alg)
+	}
+
+	return signingInput + "." + Base64URLEncode(signature), nil
+}
+
+// VerifyJWT verifies the signature of token with key and returns its claims, then checks the exp and nbf claims
+// against the current time. The algorithm is decided by the type of key rather than trusting the token header:
+// []byte or string for HS256, *rsa.PublicKey (or *rsa.PrivateKey) for RS256, a token of another algorithm is rejected.
+// It returns an error wrapping ErrJWTMalformed, ErrJWTInvalidSignature, ErrJWTExpired or ErrJWTNotValidYet.
+func VerifyJWT(token string, key any) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrJWTMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := jwtDecodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := Base64URLDecode(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signature encoding", ErrJWTMalformed)
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+
+	switch key := key.(type) {
+	case []byte, string:
+		if header.Alg != JwtHS256 {
+			return nil, fmt.Errorf("%w: unexpected algorithm %q for hmac key", ErrJWTInvalidSignature, header.Alg)
+		}
+
+		secret, err := jwtHmacSecret(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hmac.Equal(signature, jwtHmacSign(secret, signingInput)) {
+			return nil, ErrJWTInvalidSignature
+		}
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		if header.Alg != JwtRS256 {
+			return nil, fmt.Errorf("%w: unexpected algorithm %q for rsa key", ErrJWTInvalidSignature, header.Alg)
+		}
+
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			publicKey = &key.(*rsa.PrivateKey).PublicKey
+		}
+
+		hashed := sha256.Sum256([]byte(signingInput))
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature) != nil {
+			return nil, ErrJWTInvalidSignature
+		}
+	default:
+		return nil, fmt.Errorf("jwt: %w: %T", ErrUnsupportedKeyType, key)
+	}
+
+	var claims map[string]any
+	if err := jwtDecodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	// a null payload decodes to a nil map.
+	if claims == nil {
+		return nil, fmt.Errorf("%w: payload should be a json object", ErrJWTMalformed)
+	}
+
+	now := float64(time.Now().Unix())
+
+	if exp, ok := claims["exp"]; ok {
+		value, ok := exp.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: exp claim should be a number", ErrJWTMalformed)
+		}
+		if now >= value {
+			return nil, ErrJWTExpired
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		value, ok := nbf.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: nbf claim should be a number", ErrJWTMalformed)
+		}
+		if now < value {
+			return nil, ErrJWTNotValidYet
+		}
+	}
+
+	return claims, nil
+}
+
+// jwtHmacSecret returns the hmac secret of key, which should be a non-empty []byte or string.
+func jwtHmacSecret(key any) ([]byte, error) {
+	var secret []byte
+
+	switch key := key.(type) {
+	case []byte:
+		secret = key
+	case string:
+		secret = []byte(key)
+	default:
+		return nil, fmt.Errorf("jwt: %w: HS256 key should be []byte or string, got %T", ErrUnsupportedKeyType, key)
+	}
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("jwt: %w: hmac key should not be empty", ErrInvalidKeySize)
+	}
+
+	return secret, nil
+}
+
+// jwtHmacSign returns the HMAC-SHA256 of the signing input.
+func jwtHmacSign(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+
+	return mac.Sum(nil)
+}
+
+// jwtDecodeSegment decodes the base64url encoded JSON segment of a token into v.
+func jwtDecodeSegment(segment string, v any) error {
+	data, err := Base64URLDecode(segment)
+	if err != nil {
+		return fmt.Errorf("%w: invalid segment encoding", ErrJWTMalformed)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: invalid segment json", ErrJWTMalformed)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package cryptor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestSignJWT(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSignJWT")
+
+	// the example of jwt.io
+	claims := map[string]any{"sub": "1234567890", "name": "John Doe", "iat": 1516239022}
+	token, err := SignJWT(claims, []byte("your-256-bit-secret"), JwtHS256)
+	assert.IsNil(err)
+
+	header := strings.Split(token, ".")[0]
+	decoded, _ := Base64URLDecode(header)
+	assert.Equal(`{"alg":"HS256","typ":"JWT"}`, string(decoded))
+
+	verified, err := VerifyJWT(token, "your-256-bit-secret")
+	assert.IsNil(err)
+	assert.Equal("John Doe", verified["name"])
+	assert.Equal(float64(1516239022), verified["iat"])
+
+	privateKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	token, err = SignJWT(claims, privateKey, JwtRS256)
+	assert.IsNil(err)
+
+	verified, err = VerifyJWT(token, &privateKey.PublicKey)
+	assert.IsNil(err)
+	assert.Equal("1234567890", verified["sub"])
+
+	_, err = SignJWT(claims, "secret", "none")
+	assert.IsNotNil(err)
+
+	_, err = SignJWT(claims, "secret", JwtRS256)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedKeyType))
+
+	_, err = SignJWT(claims, privateKey, JwtHS256)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedKeyType))
+
+	_, err = SignJWT(claims, []byte{}, JwtHS256)
+	assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+
+	_, err = SignJWT(map[string]any{"invalid": make(chan int)}, "secret", JwtHS256)
+	assert.IsNotNil(err)
+
+	_, err = SignJWT(nil, "secret", JwtHS256)
+	assert.IsNotNil(err)
+
+	token, err = SignJWT(map[string]any{}, "secret", JwtHS256)
+	assert.IsNil(err)
+	verified, err = VerifyJWT(token, "secret")
+	assert.IsNil(err)
+	assert.Equal(map[string]any{}, verified)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestVerifyJWT")
+
+	secret := []byte("secret")
+	now := time.Now().Unix()
+
+	token, _ := SignJWT(map[string]any{"exp": now + 60, "nbf": now - 60}, secret, JwtHS256)
+	_, err := VerifyJWT(token, secret)
+	assert.IsNil(err)
+
+	_, err = VerifyJWT(token, []byte("other secret"))
+	assert.Equal(true, errors.Is(err, ErrJWTInvalidSignature))
+
+	parts := strings.Split(token, ".")
+	forgedPayload := Base64URLEncode([]byte(`{"exp":9999999999,"admin":true}`))
+	_, err = VerifyJWT(parts[0]+"."+forgedPayload+"."+parts[2], secret)
+	assert.Equal(true, errors.Is(err, ErrJWTInvalidSignature))
+
+	expired, _ := SignJWT(map[string]any{"exp": now - 1}, secret, JwtHS256)
+	_, err = VerifyJWT(expired, secret)
+	assert.Equal(true, errors.Is(err, ErrJWTExpired))
+
+	notYet, _ := SignJWT(map[string]any{"nbf": now + 60}, secret, JwtHS256)
+	_, err = VerifyJWT(notYet, secret)
+	assert.Equal(true, errors.Is(err, ErrJWTNotValidYet))
+
+	invalidExp, _ := SignJWT(map[string]any{"exp": "tomorrow"}, secret, JwtHS256)
+	_, err = VerifyJWT(invalidExp, secret)
+	assert.Equal(true, errors.Is(err, ErrJWTMalformed))
+
+	// the payload is decoded only after the signature is verified.
+	signingInput := parts[0] + "." + Base64URLEncode([]byte("[1, 2]"))
+	invalidPayload := signingInput + "." + Base64URLEncode(jwtHmacSign(secret, signingInput))
+
+	signingInput = parts[0] + "." + Base64URLEncode([]byte("null"))
+	nullPayload := signingInput + "." + Base64URLEncode(jwtHmacSign(secret, signingInput))
+
+	for _, malformed := range []string{"", "a.b", "a.b.c.d", "!!!.e30.sig", parts[0] + "." + parts[1] + ".!!!", invalidPayload, nullPayload} {
+		_, err = VerifyJWT(malformed, secret)
+		assert.Equal(true, errors.Is(err, ErrJWTMalformed))
+	}
+
+	// the algorithm is decided by the key, so a hmac token signed with the rsa public key is rejected.
+	privateKey, _ := loadRasPrivateKey("./rsa_private.pem")
+	rsaToken, _ := SignJWT(map[string]any{"sub": "1"}, privateKey, JwtRS256)
+
+	_, err = VerifyJWT(rsaToken, secret)
+	assert.Equal(true, errors.Is(err, ErrJWTInvalidSignature))
+
+	_, err = VerifyJWT(token, &privateKey.PublicKey)
+	assert.Equal(true, errors.Is(err, ErrJWTInvalidSignature))
+
+	none := Base64URLEncode([]byte(`{"alg":"none"}`)) + "." + Base64URLEncode([]byte(`{"sub":"1"}`)) + "."
+	_, err = VerifyJWT(none, secret)
+	assert.Equal(true, errors.Is(err, ErrJWTInvalidSignature))
+
+	_, err = VerifyJWT(rsaToken, privateKey)
+	assert.IsNil(err)
+
+	_, err = VerifyJWT(token, 42)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedKeyType))
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+)
+
+// HkdfKey derives a key of keyLen bytes from secret with HKDF (RFC 5869), salt and info are optional.
+// hash should be one of crypto.SHA224, crypto.SHA256, crypto.SHA384 and crypto.SHA512.
+func HkdfKey(secret, salt, info []byte, keyLen int, hash crypto.Hash) ([]byte, error) {
+	keys, err := HkdfKeys(secret, salt, info, hash, keyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys[0], nil
+}
+
+// HkdfKeys derives several keys from secret in one call with HKDF, the length of each key is specified by keyLens.
+// The keys are consecutive parts of the same HKDF output, eg. HkdfKeys(secret, salt, info, crypto.SHA256, 32, 32)
+// returns an encryption key and a MAC key.
+func HkdfKeys(secret, salt, info []byte, hash crypto.Hash, keyLens ...int) ([][]byte, error) {
+	switch hash {
+	case crypto.SHA224, crypto.SHA256, crypto.SHA384, crypto.SHA512:
+	default:
+		return nil, ErrUnsupportedHash
+	}
+
+	if len(keyLens) == 0 {
+		return nil, errors.New("hkdf: no key length specified")
+	}
+
+	total := 0
+	for _, keyLen := range keyLens {
+		if keyLen <= 0 {
+			return nil, fmt.Errorf("hkdf: invalid key length %d", keyLen)
+		}
+		total += keyLen
+	}
+
+	if total > 255*hash.Size() {
+		return nil, fmt.Errorf("hkdf: total key length %d exceeds the limit %d", total, 255*hash.Size())
+	}
+
+	reader := hkdf.New(hash.New, secret, salt, info)
+
+	keys := make([][]byte, len(keyLens))
+	for i, keyLen := range keyLens {
+		keys[i] = make([]byte, keyLen)
+		if _, err := io.ReadFull(reader, keys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// The parameters of the Argon2id key derivation used by EncryptString, as recommended by RFC 9106.
+const (
+	passwordFormatVersion = 1
+	passwordSaltSize      = 16
+	argon2Time            = 3
+	argon2Memory          = 64 * 1024
+	argon2Threads         = 4
+	argon2KeyLen          = 32
+)
+
+// EncryptString encrypts plaintext with password and returns the standard base64 encoded result.
+// The key is derived from password and a random salt with Argon2id (time 3, memory 64MB, threads 4),
+// then plaintext is encrypted with AES-256-GCM. The format of the decoded result (version 1) is:
+// version (1 byte) | salt (16 bytes) | nonce (12 bytes) | ciphertext and tag.
+func EncryptString(plaintext, password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	gcm, err := NewAesGcm(derivePasswordKey(password, salt))
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	header := append([]byte{passwordFormatVersion}, salt...)
+	header = append(header, nonce...)
+
+	// the version is authenticated as additional data.
+	ciphertext := gcm.Seal(nonce, []byte(plaintext), header[:1])
+
+	return base64.StdEncoding.EncodeToString(append(header, ciphertext...)), nil
+}
+
+// DecryptString decrypts the ciphertext returned by EncryptString with password.
+// It returns an error if ciphertext is malformed, or the password is wrong, or ciphertext is tampered.
+func DecryptString(ciphertext, password string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("kdf: failed to decode base64 ciphertext: %w", err)
+	}
+
+	if len(data) == 0 {
+		return "", fmt.Errorf("kdf: %w", ErrCiphertextTooShort)
+	}
+	if data[0] != passwordFormatVersion {
+		return "", fmt.Errorf("kdf: unsupported ciphertext format version %d", data[0])
+	}
+
+	salt := data[1:]
+	if len(salt) < passwordSaltSize {
+		return "", fmt.Errorf("kdf: %w", ErrCiphertextTooShort)
+	}
+	salt = salt[:passwordSaltSize]
+
+	gcm, err := NewAesGcm(derivePasswordKey(password, salt))
+	if err != nil {
+		return "", err
+	}
+
+	rest := data[1+passwordSaltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return "", fmt.Errorf("kdf: %w", ErrCiphertextTooShort)
+	}
+
+	plaintext, err := gcm.Open(rest[:gcm.NonceSize()], rest[gcm.NonceSize():], data[:1])
+	if err != nil {
+		return "", fmt.Errorf("kdf: %w: wrong password or tampered ciphertext", ErrDecryptionFailed)
+	}
+
+	return string(plaintext), nil
+}
+
+// derivePasswordKey derives the aes key from password and salt with Argon2id.
+func derivePasswordKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+}
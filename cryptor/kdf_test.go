@@ -0,0 +1,101 @@
+package cryptor
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestHkdfKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestHkdfKey")
+
+	// RFC 5869 test case 1
+	secret, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	expected := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	key, err := HkdfKey(secret, salt, info, 42, crypto.SHA256)
+	assert.IsNil(err)
+	assert.Equal(expected, hex.EncodeToString(key))
+
+	_, err = HkdfKey(secret, salt, info, 42, crypto.MD5)
+	assert.IsNotNil(err)
+
+	_, err = HkdfKey(secret, salt, info, 0, crypto.SHA256)
+	assert.IsNotNil(err)
+
+	_, err = HkdfKey(secret, salt, info, 255*32+1, crypto.SHA256)
+	assert.IsNotNil(err)
+}
+
+func TestHkdfKeys(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestHkdfKeys")
+
+	secret := []byte("shared secret")
+
+	keys, err := HkdfKeys(secret, nil, []byte("session"), crypto.SHA256, 16, 32)
+	assert.IsNil(err)
+	assert.Equal(2, len(keys))
+	assert.Equal(16, len(keys[0]))
+	assert.Equal(32, len(keys[1]))
+
+	key, err := HkdfKey(secret, nil, []byte("session"), 48, crypto.SHA256)
+	assert.IsNil(err)
+	assert.Equal(key[:16], keys[0])
+	assert.Equal(key[16:], keys[1])
+
+	_, err = HkdfKeys(secret, nil, nil, crypto.SHA256)
+	assert.IsNotNil(err)
+}
+
+func TestEncryptString(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEncryptString")
+
+	encrypted, err := EncryptString("hello world", "secret password")
+	assert.IsNil(err)
+
+	decrypted, err := DecryptString(encrypted, "secret password")
+	assert.IsNil(err)
+	assert.Equal("hello world", decrypted)
+
+	// random salt and nonce
+	other, err := EncryptString("hello world", "secret password")
+	assert.IsNil(err)
+	assert.NotEqual(encrypted, other)
+
+	_, err = DecryptString(encrypted, "wrong password")
+	assert.IsNotNil(err)
+
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = DecryptString(base64.StdEncoding.EncodeToString(tampered), "secret password")
+	assert.IsNotNil(err)
+
+	unknownVersion := append([]byte{}, data...)
+	unknownVersion[0] = 2
+	_, err = DecryptString(base64.StdEncoding.EncodeToString(unknownVersion), "secret password")
+	assert.Equal("kdf: unsupported ciphertext format version 2", err.Error())
+
+	_, err = DecryptString(base64.StdEncoding.EncodeToString(data[:20]), "secret password")
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+	assert.Equal("kdf: ciphertext too short", err.Error())
+
+	_, err = DecryptString("", "secret password")
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+
+	_, err = DecryptString("not base64!", "secret password")
+	assert.IsNotNil(err)
+}
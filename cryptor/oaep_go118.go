@@ -0,0 +1,22 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+//go:build !go1.20
+
+package cryptor
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+// rsaDecryptOAEP implements RSAES-OAEP decryption by crypto/rsa, which can't use a distinct MGF1 hash before Go 1.20.
+func rsaDecryptOAEP(key *rsa.PrivateKey, ciphertext, label []byte, hash, mgfHash crypto.Hash) ([]byte, error) {
+	if hash != mgfHash {
+		return nil, fmt.Errorf("rsa: %w: a MGF1 hash different from the label hash requires Go 1.20 to decrypt", ErrUnsupportedHash)
+	}
+
+	return rsa.DecryptOAEP(hash.New(), rand.Reader, key, ciphertext, label)
+}
@@ -0,0 +1,26 @@
+//go:build !go1.20
+
+package cryptor
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestRsaDecryptOAEPWithMGF(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaDecryptOAEPWithMGF")
+
+	pri, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	encrypted, err := RsaEncryptOAEPWithMGF([]byte("hello world"), nil, pri.PublicKey, crypto.SHA256, crypto.SHA1)
+	assert.IsNil(err)
+
+	_, err = RsaDecryptOAEPWithMGF(encrypted, nil, *pri, crypto.SHA256, crypto.SHA1)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedHash))
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+//go:build go1.20
+
+package cryptor
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+)
+
+// rsaDecryptOAEP implements RSAES-OAEP decryption with distinct label hash and MGF1 hash by crypto/rsa,
+// rsa.OAEPOptions.MGFHash is available since Go 1.20.
+func rsaDecryptOAEP(key *rsa.PrivateKey, ciphertext, label []byte, hash, mgfHash crypto.Hash) ([]byte, error) {
+	return key.Decrypt(rand.Reader, ciphertext, &rsa.OAEPOptions{Hash: hash, MGFHash: mgfHash, Label: label})
+}
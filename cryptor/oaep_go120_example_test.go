@@ -0,0 +1,31 @@
+//go:build go1.20
+
+package cryptor
+
+import (
+	"crypto"
+	"fmt"
+)
+
+func ExampleRsaEncryptOAEPWithMGF() {
+	pri, pub := GenerateRsaKeyPair(2048)
+
+	data := []byte("hello")
+	label := []byte("label")
+
+	// SHA-256 for the label, SHA-1 for MGF1
+	encrypted, err := RsaEncryptOAEPWithMGF(data, label, *pub, crypto.SHA256, crypto.SHA1)
+	if err != nil {
+		return
+	}
+
+	decrypted, err := RsaDecryptOAEPWithMGF(encrypted, label, *pri, crypto.SHA256, crypto.SHA1)
+	if err != nil {
+		return
+	}
+
+	fmt.Println(string(decrypted))
+
+	// Output:
+	// hello
+}
@@ -0,0 +1,32 @@
+//go:build go1.20
+
+package cryptor
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestRsaDecryptOAEPWithMGF(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRsaDecryptOAEPWithMGF")
+
+	pri, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	data := []byte("hello world")
+	label := []byte("123456")
+
+	encrypted, err := RsaEncryptOAEPWithMGF(data, label, pri.PublicKey, crypto.SHA256, crypto.SHA1)
+	assert.IsNil(err)
+
+	decrypted, err := RsaDecryptOAEPWithMGF(encrypted, label, *pri, crypto.SHA256, crypto.SHA1)
+	assert.IsNil(err)
+	assert.Equal(data, decrypted)
+
+	_, err = RsaDecryptOAEPWithMGF(encrypted, label, *pri, crypto.SHA256, crypto.SHA256)
+	assert.IsNotNil(err)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenerateHOTP returns the HMAC-based one-time password (RFC 4226) of secret and counter, with the given number of digits.
+// digits should be between 6 and 10, hash should be crypto.SHA1 (the default of most authenticator apps), crypto.SHA256 or crypto.SHA512.
+func GenerateHOTP(secret []byte, counter uint64, digits int, hash crypto.Hash) (string, error) {
+	if digits < 6 || digits > 10 {
+		return "", fmt.Errorf("otp: invalid digits %d (must be between 6 and 10)", digits)
+	}
+
+	if hash != crypto.SHA1 && hash != crypto.SHA256 && hash != crypto.SHA512 {
+		return "", fmt.Errorf("otp: %w", ErrUnsupportedHash)
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(hash.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation of RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	code := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+
+	modulo := uint64(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%modulo), nil
+}
+
+// GenerateTOTP returns the time-based one-time password (RFC 6238) of secret at t, the counter is the number of
+// periods (in seconds, usually 30) elapsed since the unix epoch. See GenerateHOTP for the digits and hash.
+func GenerateTOTP(secret []byte, t time.Time, digits int, period int, hash crypto.Hash) (string, error) {
+	counter, err := totpCounter(t, period)
+	if err != nil {
+		return "", err
+	}
+
+	return GenerateHOTP(secret, counter, digits, hash)
+}
+
+// ValidateTOTP reports whether code is the time-based one-time password of secret at t, or at up to skew periods
+// before or after t, to tolerate the clock drift and the delay of typing. skew is usually 1, the comparison is in constant time.
+// It returns false if the parameters are invalid.
+func ValidateTOTP(secret []byte, code string, t time.Time, skew int, digits int, period int, hash crypto.Hash) bool {
+	if skew < 0 || len(code) != digits {
+		return false
+	}
+
+	counter, err := totpCounter(t, period)
+	if err != nil {
+		return false
+	}
+
+	valid := false
+	for i := -skew; i <= skew; i++ {
+		if i < 0 && uint64(-i) > counter {
+			continue
+		}
+
+		expected, err := GenerateHOTP(secret, counter+uint64(i), digits, hash)
+		if err != nil {
+			return false
+		}
+
+		// keep checking the remaining periods to not leak which one matched.
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			valid = true
+		}
+	}
+
+	return valid
+}
+
+// EncodeBase32Secret encodes secret with the base32 alphabet without padding, the format of the otp secret shared
+// with authenticator apps, eg. in the otpauth:// URI.
+func EncodeBase32Secret(secret []byte) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+}
+
+// DecodeBase32Secret decodes the base32 encoded otp secret, it's case insensitive and ignores spaces, hyphens and padding,
+// eg. "JBSW Y3DP EHPK 3PXP".
+func DecodeBase32Secret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("otp: failed to decode base32 secret: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// totpCounter returns the number of periods elapsed since the unix epoch at t.
+func totpCounter(t time.Time, period int) (uint64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("otp: invalid period %d", period)
+	}
+
+	if t.Unix() < 0 {
+		return 0, fmt.Errorf("otp: time %v is before the unix epoch", t)
+	}
+
+	return uint64(t.Unix()) / uint64(period), nil
+}
@@ -0,0 +1,130 @@
+package cryptor
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestGenerateHOTP(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateHOTP")
+
+	// test values of RFC 4226 appendix D
+	secret := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for i, code := range expected {
+		result, err := GenerateHOTP(secret, uint64(i), 6, crypto.SHA1)
+		assert.IsNil(err)
+		assert.Equal(code, result)
+	}
+
+	_, err := GenerateHOTP(secret, 0, 5, crypto.SHA1)
+	assert.IsNotNil(err)
+
+	_, err = GenerateHOTP(secret, 0, 11, crypto.SHA1)
+	assert.IsNotNil(err)
+
+	_, err = GenerateHOTP(secret, 0, 6, crypto.MD5)
+	assert.Equal(true, errors.Is(err, ErrUnsupportedHash))
+}
+
+func TestGenerateTOTP(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateTOTP")
+
+	// test vectors of RFC 6238 appendix B
+	secrets := map[crypto.Hash][]byte{
+		crypto.SHA1:   []byte("12345678901234567890"),
+		crypto.SHA256: []byte("12345678901234567890123456789012"),
+		crypto.SHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	cases := []struct {
+		unix int64
+		hash crypto.Hash
+		code string
+	}{
+		{59, crypto.SHA1, "94287082"},
+		{59, crypto.SHA256, "46119246"},
+		{59, crypto.SHA512, "90693936"},
+		{1111111109, crypto.SHA1, "07081804"},
+		{1111111109, crypto.SHA256, "68084774"},
+		{1111111109, crypto.SHA512, "25091201"},
+		{1111111111, crypto.SHA1, "14050471"},
+		{1234567890, crypto.SHA1, "89005924"},
+		{2000000000, crypto.SHA1, "69279037"},
+		{20000000000, crypto.SHA1, "65353130"},
+	}
+
+	for _, c := range cases {
+		code, err := GenerateTOTP(secrets[c.hash], time.Unix(c.unix, 0), 8, 30, c.hash)
+		assert.IsNil(err)
+		assert.Equal(c.code, code)
+	}
+
+	_, err := GenerateTOTP(secrets[crypto.SHA1], time.Unix(59, 0), 6, 0, crypto.SHA1)
+	assert.IsNotNil(err)
+
+	_, err = GenerateTOTP(secrets[crypto.SHA1], time.Unix(-1, 0), 6, 30, crypto.SHA1)
+	assert.IsNotNil(err)
+}
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestValidateTOTP")
+
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+
+	code, err := GenerateTOTP(secret, now, 6, 30, crypto.SHA1)
+	assert.IsNil(err)
+
+	assert.Equal(true, ValidateTOTP(secret, code, now, 0, 6, 30, crypto.SHA1))
+	assert.Equal(true, ValidateTOTP(secret, code, now.Add(30*time.Second), 1, 6, 30, crypto.SHA1))
+	assert.Equal(true, ValidateTOTP(secret, code, now.Add(-30*time.Second), 1, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, code, now.Add(30*time.Second), 0, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, code, now.Add(90*time.Second), 1, 6, 30, crypto.SHA1))
+
+	assert.Equal(false, ValidateTOTP([]byte("other secret"), code, now, 1, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, "000000", now, 1, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, code+"0", now, 1, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, code, now, -1, 6, 30, crypto.SHA1))
+	assert.Equal(false, ValidateTOTP(secret, code, now, 1, 6, 0, crypto.SHA1))
+
+	// the skew before the epoch is ignored.
+	first, _ := GenerateTOTP(secret, time.Unix(0, 0), 6, 30, crypto.SHA1)
+	assert.Equal(true, ValidateTOTP(secret, first, time.Unix(10, 0), 2, 6, 30, crypto.SHA1))
+}
+
+func TestBase32Secret(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestBase32Secret")
+
+	secret := []byte("Hello!\xde\xad\xbe\xef")
+	encoded := EncodeBase32Secret(secret)
+	assert.Equal("JBSWY3DPEHPK3PXP", encoded)
+
+	decoded, err := DecodeBase32Secret(encoded)
+	assert.IsNil(err)
+	assert.Equal(secret, decoded)
+
+	decoded, err = DecodeBase32Secret("jbsw y3dp-ehpk 3pxp")
+	assert.IsNil(err)
+	assert.Equal(secret, decoded)
+
+	decoded, err = DecodeBase32Secret("MFRGG===")
+	assert.IsNil(err)
+	assert.Equal([]byte("abc"), decoded)
+
+	_, err = DecodeBase32Secret("invalid!")
+	assert.IsNotNil(err)
+}
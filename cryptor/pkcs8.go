@@ -0,0 +1,317 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// pkcs8Iterations is the PBKDF2 iteration count of EncryptRsaPrivateKeyPEM, the OWASP recommendation for HMAC-SHA256.
+	pkcs8Iterations = 600000
+	pkcs8SaltSize   = 16
+
+	// pkcs8MaxIterations bounds the PBKDF2 iteration count of a key to decrypt, so a crafted key can't hang the caller.
+	pkcs8MaxIterations = 10000000
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHmacWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHmacWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHmacWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHmacWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo is the EncryptedPrivateKeyInfo of RFC 5208 section 6.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params is the PBES2-params of RFC 8018 appendix A.4.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is the PBKDF2-params of RFC 8018 appendix A.2, the prf defaults to hmacWithSHA1.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// LoadEncryptedRsaPrivateKey decrypts and parses the passphrase protected PEM encoded rsa private key.
+// It supports the PKCS#8 encrypted key (PEM type "ENCRYPTED PRIVATE KEY") of PBES2 with PBKDF2 and AES-CBC or
+// DES-EDE3-CBC, the default of `openssl pkcs8 -topk8` and `openssl genpkey`, and the legacy encrypted PEM with
+// the DEK-Info header, eg. by `openssl genrsa -aes256`. The legacy format is insecure and only supported for reading.
+// An unencrypted PKCS#1 or PKCS#8 key is parsed as is.
+// It returns an error wrapping ErrDecryptionFailed if the passphrase is wrong.
+func LoadEncryptedRsaPrivateKey(pemData, passphrase []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing the private key")
+	}
+
+	var der []byte
+	var err error
+
+	// x509.DecryptPEMBlock is deprecated for the insecure format, but it's still produced by common tooling.
+	if x509.IsEncryptedPEMBlock(block) {
+		der, err = x509.DecryptPEMBlock(block, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("rsa: %w: %v", ErrDecryptionFailed, err)
+		}
+
+		privateKey, err := parseRsaPrivateKeyDER(der)
+		if err != nil {
+			return nil, fmt.Errorf("rsa: %w: incorrect passphrase or malformed key", ErrDecryptionFailed)
+		}
+		return privateKey, nil
+	}
+
+	if strings.ToUpper(block.Type) != "ENCRYPTED PRIVATE KEY" {
+		return parseRsaPrivateKey(pemData)
+	}
+
+	der, err = decryptPKCS8(block.Bytes, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	priKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("rsa: %w: incorrect passphrase or malformed key", ErrDecryptionFailed)
+	}
+
+	privateKey, ok := priKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("rsa: %w: %T", ErrUnsupportedKeyType, priKey)
+	}
+
+	return privateKey, nil
+}
+
+// EncryptRsaPrivateKeyPEM encrypts the rsa private key with passphrase into a PKCS#8 encrypted key
+// (PEM type "ENCRYPTED PRIVATE KEY") of PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC,
+// which can be loaded by LoadEncryptedRsaPrivateKey and common tooling, eg. `openssl pkey`.
+func EncryptRsaPrivateKeyPEM(priv *rsa.PrivateKey, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("rsa: passphrase should not be empty")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, pkcs8SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key(passphrase, salt, pkcs8Iterations, 32, sha256.New)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := pkcs7Padding(der, aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pkcs8Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHmacWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ivParams, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	schemeParams, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParams}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: schemeParams}},
+		EncryptedData: encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: encoded}), nil
+}
+
+// parseRsaPrivateKeyDER parses the PKCS#1 or PKCS#8 DER encoded rsa private key.
+func parseRsaPrivateKeyDER(der []byte) (*rsa.PrivateKey, error) {
+	if privateKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return privateKey, nil
+	}
+
+	priKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := priKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, priKey)
+	}
+
+	return privateKey, nil
+}
+
+// decryptPKCS8 decrypts the DER encoded PKCS#8 EncryptedPrivateKeyInfo of PBES2 and returns the DER encoded PrivateKeyInfo.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 {
+		return nil, errors.New("rsa: malformed PKCS#8 encrypted private key")
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("rsa: unsupported PKCS#8 encryption algorithm %v, only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.New("rsa: malformed PBES2 parameters")
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("rsa: unsupported key derivation function %v, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, errors.New("rsa: malformed PBKDF2 parameters")
+	}
+
+	if kdfParams.IterationCount <= 0 || kdfParams.IterationCount > pkcs8MaxIterations {
+		return nil, fmt.Errorf("rsa: invalid PBKDF2 iteration count %d", kdfParams.IterationCount)
+	}
+
+	prf, err := pbkdf2PRF(kdfParams.PRF.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	newCipher, keyLen, err := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != keyLen {
+		return nil, fmt.Errorf("rsa: %w: PBKDF2 key length %d doesn't match the cipher", ErrInvalidKeySize, kdfParams.KeyLength)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errors.New("rsa: malformed PBES2 encryption scheme parameters")
+	}
+
+	block, err := newCipher(pbkdf2.Key(passphrase, kdfParams.Salt, kdfParams.IterationCount, keyLen, prf))
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize := block.BlockSize()
+	if len(iv) != blockSize {
+		return nil, errors.New("rsa: invalid PBES2 iv length")
+	}
+
+	encrypted := info.EncryptedData
+	if len(encrypted) == 0 || len(encrypted)%blockSize != 0 {
+		return nil, fmt.Errorf("rsa: %w: encrypted key is not a multiple of the block size", ErrDecryptionFailed)
+	}
+
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, encrypted)
+
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, fmt.Errorf("rsa: %w: incorrect passphrase", ErrDecryptionFailed)
+	}
+	for _, b := range decrypted[len(decrypted)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("rsa: %w: incorrect passphrase", ErrDecryptionFailed)
+		}
+	}
+
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// pbkdf2PRF returns the hash function of the PBKDF2 prf, an empty oid is the default hmacWithSHA1.
+func pbkdf2PRF(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHmacWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHmacWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHmacWithSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHmacWithSHA512):
+		return sha512.New, nil
+	}
+
+	return nil, fmt.Errorf("rsa: %w: PBKDF2 prf %v", ErrUnsupportedHash, oid)
+}
+
+// pbes2Cipher returns the block cipher constructor and the key length of the PBES2 encryption scheme.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func(key []byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, nil
+	}
+
+	return nil, 0, fmt.Errorf("rsa: unsupported PBES2 encryption scheme %v", oid)
+}
@@ -0,0 +1,80 @@
+package cryptor
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestLoadEncryptedRsaPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestLoadEncryptedRsaPrivateKey")
+
+	// the key encrypted by `openssl pkcs8 -topk8`, aes-256-cbc with hmacWithSHA256.
+	pkcs8PEM, err := os.ReadFile("./rsa_private_encrypted.pem")
+	assert.IsNil(err)
+
+	// the same key encrypted by `openssl rsa -aes256 -traditional`, with the DEK-Info header.
+	legacyPEM, err := os.ReadFile("./rsa_private_encrypted_legacy.pem")
+	assert.IsNil(err)
+
+	privateKey, err := LoadEncryptedRsaPrivateKey(pkcs8PEM, []byte("lancet"))
+	assert.IsNil(err)
+	assert.IsNil(privateKey.Validate())
+
+	legacyKey, err := LoadEncryptedRsaPrivateKey(legacyPEM, []byte("lancet"))
+	assert.IsNil(err)
+	assert.Equal(true, privateKey.Equal(legacyKey))
+
+	_, err = LoadEncryptedRsaPrivateKey(pkcs8PEM, []byte("wrong passphrase"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = LoadEncryptedRsaPrivateKey(legacyPEM, []byte("wrong passphrase"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = LoadEncryptedRsaPrivateKey([]byte("not a pem"), []byte("lancet"))
+	assert.IsNotNil(err)
+}
+
+func TestEncryptRsaPrivateKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEncryptRsaPrivateKeyPEM")
+
+	privateKey, err := loadRasPrivateKey("./rsa_private.pem")
+	assert.IsNil(err)
+
+	encrypted, err := EncryptRsaPrivateKeyPEM(privateKey, []byte("lancet"))
+	assert.IsNil(err)
+	assert.Equal(true, len(encrypted) > 0)
+
+	// the key is not readable without the passphrase.
+	_, err = parseRsaPrivateKey(encrypted)
+	assert.IsNotNil(err)
+
+	decrypted, err := LoadEncryptedRsaPrivateKey(encrypted, []byte("lancet"))
+	assert.IsNil(err)
+	assert.Equal(true, privateKey.Equal(decrypted))
+
+	_, err = LoadEncryptedRsaPrivateKey(encrypted, []byte("wrong passphrase"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	// the salt and iv are random.
+	again, err := EncryptRsaPrivateKeyPEM(privateKey, []byte("lancet"))
+	assert.IsNil(err)
+	assert.NotEqual(string(encrypted), string(again))
+
+	_, err = EncryptRsaPrivateKeyPEM(privateKey, nil)
+	assert.IsNotNil(err)
+
+	// an unencrypted key is parsed as is.
+	plainPEM, err := os.ReadFile("./rsa_private.pem")
+	assert.IsNil(err)
+
+	plainKey, err := LoadEncryptedRsaPrivateKey(plainPEM, nil)
+	assert.IsNil(err)
+	assert.Equal(true, privateKey.Equal(plainKey))
+}
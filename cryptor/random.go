@@ -0,0 +1,42 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// GenerateRandomBytes returns n bytes read from the cryptographically secure random number generator.
+func GenerateRandomBytes(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid random bytes length %d", n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// GenerateAesKey returns a random aes key, bits should be 128, 192 or 256.
+func GenerateAesKey(bits int) ([]byte, error) {
+	if bits != 128 && bits != 192 && bits != 256 {
+		return nil, fmt.Errorf("aes: %w (must be 128, 192, or 256 bits)", ErrInvalidKeySize)
+	}
+
+	return GenerateRandomBytes(bits / 8)
+}
+
+// GenerateNonce returns a random nonce of size bytes, eg. 12 for AES GCM, 24 for XChaCha20-Poly1305.
+func GenerateNonce(size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid nonce size %d", size)
+	}
+
+	return GenerateRandomBytes(size)
+}
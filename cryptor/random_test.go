@@ -0,0 +1,60 @@
+package cryptor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestGenerateRandomBytes(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateRandomBytes")
+
+	b1, err := GenerateRandomBytes(32)
+	assert.IsNil(err)
+	assert.Equal(32, len(b1))
+
+	b2, err := GenerateRandomBytes(32)
+	assert.IsNil(err)
+	assert.Equal(false, bytes.Equal(b1, b2))
+
+	b, err := GenerateRandomBytes(0)
+	assert.IsNil(err)
+	assert.Equal(0, len(b))
+
+	_, err = GenerateRandomBytes(-1)
+	assert.IsNotNil(err)
+}
+
+func TestGenerateAesKey(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateAesKey")
+
+	for _, bits := range []int{128, 192, 256} {
+		key, err := GenerateAesKey(bits)
+		assert.IsNil(err)
+		assert.Equal(bits/8, len(key))
+
+		_, err = NewAesCipher(key)
+		assert.IsNil(err)
+	}
+
+	_, err := GenerateAesKey(16)
+	assert.IsNotNil(err)
+}
+
+func TestGenerateNonce(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateNonce")
+
+	nonce, err := GenerateNonce(12)
+	assert.IsNil(err)
+	assert.Equal(12, len(nonce))
+
+	_, err = GenerateNonce(0)
+	assert.IsNotNil(err)
+}
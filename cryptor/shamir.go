@@ -0,0 +1,144 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxSecretShares is the max number of shares, the x coordinates of the shares are the non-zero elements of GF(256).
+const maxSecretShares = 255
+
+// SplitSecret splits secret into parts shares with Shamir's Secret Sharing over GF(256), any threshold of them
+// can reconstruct the secret by CombineSecret, while fewer reveal nothing about it.
+// Each share is 2 bytes longer than secret: x coordinate (1 byte) | threshold (1 byte) | y values.
+// parts should be in [1, 255] and threshold should be in [1, parts].
+func SplitSecret(secret []byte, parts, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("shamir: secret should not be empty")
+	}
+	if parts <= 0 || parts > maxSecretShares {
+		return nil, fmt.Errorf("shamir: invalid parts %d (must be between 1 and %d)", parts, maxSecretShares)
+	}
+	if threshold <= 0 || threshold > parts {
+		return nil, fmt.Errorf("shamir: invalid threshold %d (must be between 1 and parts)", threshold)
+	}
+
+	shares := make([][]byte, parts)
+	for i := range shares {
+		shares[i] = make([]byte, 2+len(secret))
+		shares[i][0] = byte(i + 1)
+		shares[i][1] = byte(threshold)
+	}
+
+	// a random polynomial of degree threshold-1 per secret byte, whose constant term is the byte.
+	coefficients := make([]byte, threshold)
+	for i, b := range secret {
+		coefficients[0] = b
+		if _, err := io.ReadFull(rand.Reader, coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		for _, share := range shares {
+			share[2+i] = gfEvaluate(coefficients, share[0])
+		}
+	}
+
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+
+	return shares, nil
+}
+
+// CombineSecret reconstructs the secret from the shares returned by SplitSecret.
+// It returns an error if the shares are malformed, inconsistent, duplicated or fewer than the threshold.
+func CombineSecret(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("shamir: no shares")
+	}
+
+	size := len(shares[0])
+	if size < 3 {
+		return nil, errors.New("shamir: malformed share")
+	}
+	threshold := int(shares[0][1])
+
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shamir: shares have different lengths")
+		}
+		if share[0] == 0 || share[1] == 0 {
+			return nil, errors.New("shamir: malformed share")
+		}
+		if int(share[1]) != threshold {
+			return nil, errors.New("shamir: shares have different thresholds")
+		}
+		if seen[share[0]] {
+			return nil, errors.New("shamir: duplicated share")
+		}
+		seen[share[0]] = true
+	}
+
+	if len(shares) < threshold {
+		return nil, fmt.Errorf("shamir: insufficient shares, need %d but got %d", threshold, len(shares))
+	}
+	shares = shares[:threshold]
+
+	secret := make([]byte, size-2)
+	for i := range secret {
+		// Lagrange interpolation at x = 0.
+		var value byte
+		for j, share := range shares {
+			basis := byte(1)
+			for k, other := range shares {
+				if j != k {
+					basis = gfMul(basis, gfDiv(other[0], other[0]^share[0]))
+				}
+			}
+			value ^= gfMul(share[2+i], basis)
+		}
+		secret[i] = value
+	}
+
+	return secret, nil
+}
+
+// gfEvaluate evaluates the polynomial with coefficients in ascending order at x over GF(256), by Horner's method.
+func gfEvaluate(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+
+	return result
+}
+
+// gfMul multiplies a and b in GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1, it runs in constant time.
+func gfMul(a, b byte) byte {
+	var product byte
+	for i := 0; i < 8; i++ {
+		product ^= -(b & 1) & a
+		carry := -(a >> 7)
+		a = (a << 1) ^ (0x1b & carry)
+		b >>= 1
+	}
+
+	return product
+}
+
+// gfDiv divides a by b in GF(256), b should not be 0. The inverse of b is b^254.
+func gfDiv(a, b byte) byte {
+	inverse := b
+	for i := 0; i < 6; i++ {
+		inverse = gfMul(gfMul(inverse, inverse), b)
+	}
+	inverse = gfMul(inverse, inverse)
+
+	return gfMul(a, inverse)
+}
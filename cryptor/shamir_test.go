@@ -0,0 +1,113 @@
+package cryptor
+
+import (
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func TestSplitSecret(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSplitSecret")
+
+	secret := []byte("master key 0123456789")
+
+	shares, err := SplitSecret(secret, 5, 3)
+	assert.IsNil(err)
+	assert.Equal(5, len(shares))
+	for _, share := range shares {
+		assert.Equal(len(secret)+2, len(share))
+	}
+
+	// any 3 of 5 shares reconstruct the secret.
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				combined, err := CombineSecret([][]byte{shares[k], shares[i], shares[j]})
+				assert.IsNil(err)
+				assert.Equal(secret, combined)
+			}
+		}
+	}
+
+	combined, err := CombineSecret(shares)
+	assert.IsNil(err)
+	assert.Equal(secret, combined)
+
+	single, err := SplitSecret(secret, 3, 1)
+	assert.IsNil(err)
+	combined, err = CombineSecret(single[2:])
+	assert.IsNil(err)
+	assert.Equal(secret, combined)
+
+	_, err = SplitSecret(nil, 5, 3)
+	assert.IsNotNil(err)
+
+	_, err = SplitSecret(secret, 3, 5)
+	assert.IsNotNil(err)
+
+	_, err = SplitSecret(secret, 0, 0)
+	assert.IsNotNil(err)
+
+	_, err = SplitSecret(secret, 5, 0)
+	assert.IsNotNil(err)
+
+	_, err = SplitSecret(secret, 256, 3)
+	assert.IsNotNil(err)
+}
+
+func TestCombineSecret(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCombineSecret")
+
+	secret := []byte("secret")
+	shares, _ := SplitSecret(secret, 5, 3)
+
+	_, err := CombineSecret(nil)
+	assert.IsNotNil(err)
+
+	_, err = CombineSecret(shares[:2])
+	assert.IsNotNil(err)
+
+	_, err = CombineSecret([][]byte{shares[0], shares[1], shares[1]})
+	assert.IsNotNil(err)
+
+	_, err = CombineSecret([][]byte{shares[0], shares[1], shares[2][:5]})
+	assert.IsNotNil(err)
+
+	_, err = CombineSecret([][]byte{{1, 3}})
+	assert.IsNotNil(err)
+
+	zeroX := append([]byte{}, shares[2]...)
+	zeroX[0] = 0
+	_, err = CombineSecret([][]byte{shares[0], shares[1], zeroX})
+	assert.IsNotNil(err)
+
+	others, _ := SplitSecret(secret, 5, 2)
+	_, err = CombineSecret([][]byte{shares[0], shares[1], others[2]})
+	assert.IsNotNil(err)
+
+	// a tampered share yields a different secret.
+	tampered := append([]byte{}, shares[2]...)
+	tampered[2] ^= 0xff
+	combined, err := CombineSecret([][]byte{shares[0], shares[1], tampered})
+	assert.IsNil(err)
+	assert.NotEqual(secret, combined)
+}
+
+func TestGfArithmetic(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGfArithmetic")
+
+	// example of FIPS 197 section 4.2
+	assert.Equal(byte(0xc1), gfMul(0x57, 0x83))
+	assert.Equal(byte(0xfe), gfMul(0x57, 0x13))
+
+	for a := 1; a < 256; a++ {
+		assert.Equal(byte(1), gfMul(gfDiv(1, byte(a)), byte(a)))
+		assert.Equal(byte(a), gfDiv(gfMul(byte(a), 0x53), 0x53))
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package cryptor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"fmt"
+)
+
+// AesSivEncrypt encrypts data with key use AES-SIV algorithm (RFC 5297), a deterministic authenticated encryption:
+// the same data, key and aad always produce the same ciphertext, eg. for the equality lookups on encrypted columns.
+// Security: it leaks whether two ciphertexts have the same plaintext, use a randomized cipher like AES-GCM unless
+// the equality is required. The aad components, eg. the column name, are authenticated but not encrypted.
+// len(key) should be 32, 48 or 64, the first half is the MAC key and the second half is the CTR key.
+// The result is the 16 bytes synthetic IV followed by the ciphertext.
+func AesSivEncrypt(data, key []byte, aad ...[]byte) ([]byte, error) {
+	macBlock, ctrBlock, err := newSivCiphers(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v := s2v(macBlock, aad, data)
+
+	result := make([]byte, aes.BlockSize+len(data))
+	copy(result, v)
+	cipher.NewCTR(ctrBlock, sivCounter(v)).XORKeyStream(result[aes.BlockSize:], data)
+
+	return result, nil
+}
+
+// AesSivDecrypt decrypts data encrypted by AesSivEncrypt with key and the same aad.
+// It returns an error wrapping ErrDecryptionFailed if data is tampered or key or aad is wrong.
+func AesSivDecrypt(data, key []byte, aad ...[]byte) ([]byte, error) {
+	macBlock, ctrBlock, err := newSivCiphers(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aes.BlockSize {
+		return nil, fmt.Errorf("aes-siv: %w", ErrCiphertextTooShort)
+	}
+
+	v, ciphertext := data[:aes.BlockSize], data[aes.BlockSize:]
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(ctrBlock, sivCounter(v)).XORKeyStream(plaintext, ciphertext)
+
+	if subtle.ConstantTimeCompare(v, s2v(macBlock, aad, plaintext)) != 1 {
+		return nil, fmt.Errorf("aes-siv: %w: message authentication failed", ErrDecryptionFailed)
+	}
+
+	return plaintext, nil
+}
+
+// newSivCiphers returns the MAC cipher and CTR cipher of the AES-SIV key.
+func newSivCiphers(key []byte) (cipher.Block, cipher.Block, error) {
+	if len(key) != 32 && len(key) != 48 && len(key) != 64 {
+		return nil, nil, fmt.Errorf("aes-siv: %w (must be 32, 48, or 64 bytes)", ErrInvalidKeySize)
+	}
+
+	half := len(key) / 2
+
+	macBlock, err := aes.NewCipher(key[:half])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctrBlock, err := aes.NewCipher(key[half:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return macBlock, ctrBlock, nil
+}
+
+// sivCounter returns the initial counter of CTR mode, that is v with the 31st and 63rd bits (from the right) cleared.
+func sivCounter(v []byte) []byte {
+	counter := append([]byte{}, v...)
+	counter[8] &= 0x7f
+	counter[12] &= 0x7f
+
+	return counter
+}
+
+// s2v implements the S2V construction of RFC 5297 section 2.4 over the strings aad... and plaintext.
+func s2v(block cipher.Block, aad [][]byte, plaintext []byte) []byte {
+	d := cmac(block, make([]byte, aes.BlockSize))
+
+	for _, s := range aad {
+		d = dbl(d)
+		xorBytes(d, cmac(block, s))
+	}
+
+	var t []byte
+	if len(plaintext) >= aes.BlockSize {
+		// xorend: xor d into the last 16 bytes of the plaintext.
+		t = append([]byte{}, plaintext...)
+		xorBytes(t[len(t)-aes.BlockSize:], d)
+	} else {
+		t = dbl(d)
+		xorBytes(t, cmacPad(plaintext))
+	}
+
+	return cmac(block, t)
+}
+
+// cmac returns the AES-CMAC (RFC 4493) of msg.
+func cmac(block cipher.Block, msg []byte) []byte {
+	k1 := make([]byte, aes.BlockSize)
+	block.Encrypt(k1, k1)
+	k1 = dbl(k1)
+	k2 := dbl(k1)
+
+	blocks := (len(msg) + aes.BlockSize - 1) / aes.BlockSize
+	if blocks == 0 {
+		blocks = 1
+	}
+
+	last := make([]byte, aes.BlockSize)
+	tail := msg[(blocks-1)*aes.BlockSize:]
+	if len(tail) == aes.BlockSize {
+		copy(last, tail)
+		xorBytes(last, k1)
+	} else {
+		copy(last, cmacPad(tail))
+		xorBytes(last, k2)
+	}
+
+	mac := make([]byte, aes.BlockSize)
+	for i := 0; i < blocks-1; i++ {
+		xorBytes(mac, msg[i*aes.BlockSize:(i+1)*aes.BlockSize])
+		block.Encrypt(mac, mac)
+	}
+	xorBytes(mac, last)
+	block.Encrypt(mac, mac)
+
+	return mac
+}
+
+// cmacPad pads the partial block b with a single 1 bit followed by 0 bits to the block size.
+func cmacPad(b []byte) []byte {
+	padded := make([]byte, aes.BlockSize)
+	copy(padded, b)
+	padded[len(b)] = 0x80
+
+	return padded
+}
+
+// dbl returns the multiplication of the block b by x in GF(2^128).
+func dbl(b []byte) []byte {
+	result := make([]byte, len(b))
+
+	var carry byte
+	for i := len(b) - 1; i >= 0; i-- {
+		result[i] = b[i]<<1 | carry
+		carry = b[i] >> 7
+	}
+
+	// constant time conditional xor of the reduction polynomial.
+	result[len(result)-1] ^= 0x87 & -carry
+
+	return result
+}
+
+// xorBytes xors src into dst, len(dst) should be no more than len(src).
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,128 @@
+package cryptor
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/duke-git/lancet/v2/internal"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestCmac(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCmac")
+
+	// test vectors of RFC 4493 section 4
+	block, _ := aes.NewCipher(decodeHex("2b7e1516 28aed2a6 abf71588 09cf4f3c"))
+
+	cases := map[string]string{
+		"":                                    "bb1d6929 e9593728 7fa37d12 9b756746",
+		"6bc1bee2 2e409f96 e93d7e11 7393172a": "070a16b4 6b4d4144 f79bdd9d d04a287c",
+		"6bc1bee2 2e409f96 e93d7e11 7393172a ae2d8a57 1e03ac9c 9eb76fac 45af8e51 30c81c46 a35ce411": "dfa66747 de9ae630 30ca3261 1497c827",
+	}
+
+	for msg, expected := range cases {
+		assert.Equal(decodeHex(expected), cmac(block, decodeHex(msg)))
+	}
+}
+
+func TestAesSivEncrypt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesSivEncrypt")
+
+	// deterministic authenticated encryption example of RFC 5297 appendix A.1
+	key := decodeHex("fffefdfc fbfaf9f8 f7f6f5f4 f3f2f1f0 f0f1f2f3 f4f5f6f7 f8f9fafb fcfdfeff")
+	aad := decodeHex("10111213 14151617 18191a1b 1c1d1e1f 20212223 24252627")
+	plaintext := decodeHex("11223344 55667788 99aabbcc ddee")
+	expected := decodeHex("85632d07 c6e8f37f 950acd32 0a2ecc93 40c02b96 90c4dc04 daef7f6a fe5c")
+
+	encrypted, err := AesSivEncrypt(plaintext, key, aad)
+	assert.IsNil(err)
+	assert.Equal(expected, encrypted)
+
+	decrypted, err := AesSivDecrypt(encrypted, key, aad)
+	assert.IsNil(err)
+	assert.Equal(plaintext, decrypted)
+
+	// nonce-based authenticated encryption example of RFC 5297 appendix A.2, the nonce is the last aad component.
+	key = decodeHex("7f7e7d7c 7b7a7978 77767574 73727170 40414243 44454647 48494a4b 4c4d4e4f")
+	ad1 := decodeHex("00112233 44556677 8899aabb ccddeeff deaddada deaddada ffeeddcc bbaa9988 77665544 33221100")
+	ad2 := decodeHex("10203040 50607080 90a0")
+	nonce := decodeHex("09f91102 9d74e35b d84156c5 635688c0")
+	plaintext = decodeHex("74686973 20697320 736f6d65 20706c61 696e7465 78742074 6f20656e 63727970 74207573 696e6720 5349562d 414553")
+	expected = decodeHex("7bdb6e3b 432667eb 06f4d14b ff2fbd0f cb900f2f ddbe4043 26601965 c889bf17 dba77ceb 094fa663 b7a3f748 ba8af829 ea64ad54 4a272e9c 485b62a3 fd5c0d")
+
+	encrypted, err = AesSivEncrypt(plaintext, key, ad1, ad2, nonce)
+	assert.IsNil(err)
+	assert.Equal(expected, encrypted)
+
+	decrypted, err = AesSivDecrypt(encrypted, key, ad1, ad2, nonce)
+	assert.IsNil(err)
+	assert.Equal(plaintext, decrypted)
+}
+
+func TestAesSivDecrypt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAesSivDecrypt")
+
+	key := []byte("abcdefghijklmnopqrstuvwxyz012345")
+
+	for _, size := range []int{0, 1, 15, 16, 17, 100} {
+		data := []byte(strings.Repeat("x", size))
+
+		encrypted, err := AesSivEncrypt(data, key)
+		assert.IsNil(err)
+		assert.Equal(aes.BlockSize+size, len(encrypted))
+
+		// deterministic
+		again, _ := AesSivEncrypt(data, key)
+		assert.Equal(encrypted, again)
+
+		decrypted, err := AesSivDecrypt(encrypted, key)
+		assert.IsNil(err)
+		assert.Equal(data, decrypted)
+	}
+
+	encrypted, _ := AesSivEncrypt([]byte("alice@example.com"), key, []byte("email"))
+
+	_, err := AesSivDecrypt(encrypted, key, []byte("name"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = AesSivDecrypt(encrypted, key)
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0x01
+	_, err = AesSivDecrypt(tampered, key, []byte("email"))
+	assert.Equal(true, errors.Is(err, ErrDecryptionFailed))
+
+	_, err = AesSivDecrypt(encrypted[:10], key, []byte("email"))
+	assert.Equal(true, errors.Is(err, ErrCiphertextTooShort))
+
+	for _, keyLen := range []int{16, 24, 31, 65} {
+		_, err = AesSivEncrypt([]byte("hello"), make([]byte, keyLen))
+		assert.Equal(true, errors.Is(err, ErrInvalidKeySize))
+	}
+
+	for _, keyLen := range []int{48, 64} {
+		longKey := []byte(strings.Repeat("k", keyLen))
+		encrypted, err := AesSivEncrypt([]byte("hello"), longKey)
+		assert.IsNil(err)
+		decrypted, err := AesSivDecrypt(encrypted, longKey)
+		assert.IsNil(err)
+		assert.Equal([]byte("hello"), decrypted)
+	}
+}
@@ -6,3 +6,8 @@ require (
 	golang.org/x/exp v0.0.0-20221208152030-732eee02a75a
 	golang.org/x/text v0.9.0
 )
+
+require (
+	golang.org/x/crypto v0.9.0
+	golang.org/x/sys v0.8.0 // indirect
+)
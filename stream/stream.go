@@ -7,9 +7,16 @@ package stream
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/duke-git/lancet/v2/slice"
 	"golang.org/x/exp/constraints"
 )
 
@@ -47,8 +54,79 @@ import (
 // 	Concat(streams ...StreamI[T]) StreamI[T]
 // }
 
+// Stream is a sequence of elements. A stream created by FromSlice, Of or FromRange is backed by the slice directly,
+// other streams are lazy: their elements are computed only when a terminal operation pulls them.
 type Stream[T any] struct {
 	source []T
+	// pull creates an iterator over the elements of a lazy stream, it is nil if the stream is backed by source.
+	pull func() func() (T, bool)
+	ctx  context.Context
+}
+
+// newStream creates a lazy stream which gets its elements from the iterator created by pull.
+func newStream[T any](ctx context.Context, pull func() func() (T, bool)) Stream[T] {
+	return Stream[T]{pull: pull, ctx: ctx}
+}
+
+// firstContext returns the context of the first stream, it's used by the functions which combine several streams.
+func firstContext[T any](streams []Stream[T]) context.Context {
+	if len(streams) == 0 {
+		return nil
+	}
+	return streams[0].ctx
+}
+
+// sliceIterator returns an iterator over the elements of source.
+func sliceIterator[T any](source []T) func() (T, bool) {
+	index := 0
+
+	return func() (T, bool) {
+		if index >= len(source) {
+			var zeroValue T
+			return zeroValue, false
+		}
+
+		index++
+		return source[index-1], true
+	}
+}
+
+// maxCapacityHint bounds the capacity allocated up front for a size given by the caller,
+// so a huge size doesn't allocate before any element is pulled. The slice grows by append beyond it.
+const maxCapacityHint = 1024
+
+// capacityHint returns the capacity to allocate up front for a slice which holds at most size elements.
+func capacityHint(size int) int {
+	if size > maxCapacityHint {
+		return maxCapacityHint
+	}
+	return size
+}
+
+// iterator returns a function which yields the elements of the stream one by one.
+// ok is false once the stream is exhausted or the context of the stream is done.
+func (s Stream[T]) iterator() func() (item T, ok bool) {
+	var next func() (T, bool)
+	if s.pull == nil {
+		next = sliceIterator(s.source)
+	} else {
+		next = s.pull()
+	}
+
+	if s.ctx == nil {
+		return next
+	}
+
+	ctx := s.ctx
+	return func() (T, bool) {
+		select {
+		case <-ctx.Done():
+			var zeroValue T
+			return zeroValue, false
+		default:
+			return next()
+		}
+	}
 }
 
 // Of creates a stream whose elements are the specified values.
@@ -57,21 +135,81 @@ func Of[T any](elems ...T) Stream[T] {
 	return FromSlice(elems)
 }
 
-// Generate stream where each element is generated by the provided generater function
+// Generate stream where each element is generated by the provided generater function.
+// The generator is called lazily, when a terminal operation of the stream is performed.
 // Play: https://go.dev/play/p/rkOWL1yA3j9
 func Generate[T any](generator func() func() (item T, ok bool)) Stream[T] {
-	source := make([]T, 0)
+	return newStream(nil, func() func() (T, bool) {
+		next, done := generator(), false
+
+		return func() (T, bool) {
+			if done {
+				var zeroValue T
+				return zeroValue, false
+			}
+
+			item, ok := next()
+			done = !ok
+			return item, ok
+		}
+	})
+}
 
-	var zeroValue T
-	for next, item, ok := generator(), zeroValue, true; ok; {
+// GenerateN creates a stream of n elements, the element at index i is generator(i).
+// The generator is called exactly n times at each traversal of the stream, a non-positive n creates an empty stream.
+func GenerateN[T any](n int, generator func(index int) T) Stream[T] {
+	return newStream(nil, func() func() (T, bool) {
+		index := 0
 
-		item, ok = next()
-		if ok {
-			source = append(source, item)
+		return func() (T, bool) {
+			if index >= n {
+				var zeroValue T
+				return zeroValue, false
+			}
+
+			index++
+			return generator(index - 1), true
 		}
-	}
+	})
+}
 
-	return FromSlice(source)
+// Iterate creates an infinite lazy stream whose elements are seed, next(seed), next(next(seed)), and so on.
+// The stream should be bounded by operations like Limit before performing a terminal operation.
+// eg. Iterate(1, func(n int) int { return n * 2 }).Limit(5) => [1 2 4 8 16]
+func Iterate[T any](seed T, next func(item T) T) Stream[T] {
+	return newStream(nil, func() func() (T, bool) {
+		current, started := seed, false
+
+		return func() (T, bool) {
+			if started {
+				current = next(current)
+			}
+			started = true
+			return current, true
+		}
+	})
+}
+
+// Empty creates a stream without any element.
+func Empty[T any]() Stream[T] {
+	return FromSlice([]T{})
+}
+
+// Repeat creates a stream which yields value count times, a non-positive count creates an empty stream.
+func Repeat[T any](value T, count int) Stream[T] {
+	return GenerateN(count, func(_ int) T {
+		return value
+	})
+}
+
+// RepeatInfinite creates an infinite lazy stream which yields value repeatedly.
+// The stream should be bounded by operations like Limit before performing a terminal operation.
+func RepeatInfinite[T any](value T) Stream[T] {
+	return newStream(nil, func() func() (T, bool) {
+		return func() (T, bool) {
+			return value, true
+		}
+	})
 }
 
 // FromSlice creates stream from slice.
@@ -80,16 +218,16 @@ func FromSlice[T any](source []T) Stream[T] {
 	return Stream[T]{source: source}
 }
 
-// FromChannel creates stream from channel.
+// FromChannel creates stream from channel. The elements are received lazily until the channel is closed,
+// so the stream can be traversed only once.
 // Play: https://go.dev/play/p/9TZYugGMhXZ
 func FromChannel[T any](source <-chan T) Stream[T] {
-	s := make([]T, 0)
-
-	for v := range source {
-		s = append(s, v)
-	}
-
-	return FromSlice(s)
+	return newStream(nil, func() func() (T, bool) {
+		return func() (T, bool) {
+			item, ok := <-source
+			return item, ok
+		}
+	})
 }
 
 // FromRange creates a number stream from start to end. both start and end are included. [start, end]
@@ -111,33 +249,132 @@ func FromRange[T constraints.Integer | constraints.Float](start, end, step T) St
 	return FromSlice(source)
 }
 
-// Concat creates a lazily concatenated stream whose elements are all the elements of the first stream followed by all the elements of the second stream.
+// Concat creates a lazily concatenated stream whose elements are all the elements of the first stream followed by
+// all the elements of the second stream, and so on.
 // Play: https://go.dev/play/p/HM4OlYk_OUC
-func Concat[T any](a, b Stream[T]) Stream[T] {
-	source := make([]T, 0)
+func Concat[T any](streams ...Stream[T]) Stream[T] {
+	return newStream(firstContext(streams), func() func() (T, bool) {
+		var next func() (T, bool)
+		index := 0
+
+		return func() (T, bool) {
+			for {
+				if next == nil {
+					if index >= len(streams) {
+						var zeroValue T
+						return zeroValue, false
+					}
+					next = streams[index].iterator()
+					index++
+				}
+
+				if item, ok := next(); ok {
+					return item, true
+				}
+				next = nil
+			}
+		}
+	})
+}
 
-	source = append(source, a.source...)
-	source = append(source, b.source...)
+// Append returns a lazy stream whose elements are the elements of this stream followed by items.
+func (s Stream[T]) Append(items ...T) Stream[T] {
+	return Concat(s, FromSlice(items))
+}
 
-	return FromSlice(source)
+// Prepend returns a lazy stream whose elements are items followed by the elements of this stream.
+// eg. Of(3, 4).Prepend(1, 2) => [1 2 3 4]
+func (s Stream[T]) Prepend(items ...T) Stream[T] {
+	// the items stream takes the context of s, so that the result keeps it.
+	return Concat(Stream[T]{source: items, ctx: s.ctx}, s)
+}
+
+// Intersperse returns a lazy stream which inserts sep between each pair of adjacent elements of this stream,
+// eg. Of(1, 2, 3).Intersperse(0) => [1 0 2 0 3]. The empty and single element streams have no separator.
+func (s Stream[T]) Intersperse(sep T) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+
+		var pending T
+		hasPending, started := false, false
+
+		return func() (T, bool) {
+			if hasPending {
+				hasPending = false
+				return pending, true
+			}
+
+			item, ok := next()
+			if !ok {
+				return item, false
+			}
+
+			if !started {
+				started = true
+				return item, true
+			}
+
+			// yield the separator first, the element is yielded by the next call.
+			pending, hasPending = item, true
+			return sep, true
+		}
+	})
+}
+
+// WithContext returns a stream which stops yielding elements once the ctx is done, terminal operations
+// will end early and return whatever was accumulated so far. The streams derived from it share the ctx.
+// Note: the cancellation is checked between elements, not in the middle of an operation, eg. a blocking
+// receive of the stream created by FromChannel will not be interrupted.
+func (s Stream[T]) WithContext(ctx context.Context) Stream[T] {
+	s.ctx = ctx
+	return s
+}
+
+// Cache returns a stream which computes the elements of this stream at the first terminal operation and
+// keeps them in memory, the later terminal operations traverse the cached elements without recomputing.
+// It's worthwhile when several terminal operations are performed on an expensive pipeline (eg. heavy Map or Filter),
+// or on a stream which can be traversed only once, like the stream created by FromChannel.
+func (s Stream[T]) Cache() Stream[T] {
+	var once sync.Once
+	var cached []T
+
+	return newStream(s.ctx, func() func() (T, bool) {
+		once.Do(func() {
+			cached = s.ToSlice()
+		})
+		return sliceIterator(cached)
+	})
 }
 
 // Distinct returns a stream that removes the duplicated items.
+// The items are compared by their gob encoding, which is slow, ignores unexported fields and panics on the types
+// gob can't encode, such as functions and channels. For comparable item types, use DistinctComparable which is much faster,
+// otherwise use DistinctByHash with a custom hash function.
 // Play: https://go.dev/play/p/eGkOSrm64cB
 func (s Stream[T]) Distinct() Stream[T] {
-	source := make([]T, 0)
-
-	distinct := map[string]bool{}
-
-	for _, v := range s.source {
-		k := hashKey(v)
-		if _, ok := distinct[k]; !ok {
-			distinct[k] = true
-			source = append(source, v)
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+		distinct := map[string]bool{}
+
+		return func() (T, bool) {
+			for item, ok := next(); ok; item, ok = next() {
+				k := hashKey(item)
+				if _, ok := distinct[k]; !ok {
+					distinct[k] = true
+					return item, true
+				}
+			}
+
+			var zeroValue T
+			return zeroValue, false
 		}
-	}
+	})
+}
 
-	return FromSlice(source)
+// DistinctByHash returns a stream that removes the duplicated items, two items are duplicated if hashFn returns the same string for them.
+// It's a fast and reliable alternative of Distinct for the types which gob can't encode well.
+func (s Stream[T]) DistinctByHash(hashFn func(item T) string) Stream[T] {
+	return DistinctBy(s, hashFn)
 }
 
 func hashKey(data any) string {
@@ -153,37 +390,87 @@ func hashKey(data any) string {
 // Filter returns a stream consisting of the elements of this stream that match the given predicate.
 // Play: https://go.dev/play/p/MFlSANo-buc
 func (s Stream[T]) Filter(predicate func(item T) bool) Stream[T] {
-	source := make([]T, 0)
-
-	for _, v := range s.source {
-		if predicate(v) {
-			source = append(source, v)
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+
+		return func() (T, bool) {
+			for item, ok := next(); ok; item, ok = next() {
+				if predicate(item) {
+					return item, true
+				}
+			}
+
+			var zeroValue T
+			return zeroValue, false
 		}
-	}
-
-	return FromSlice(source)
+	})
 }
 
 // Map returns a stream consisting of the elements of this stream that apply the given function to elements of stream.
 // Play: https://go.dev/play/p/OtNQUImdYko
 func (s Stream[T]) Map(mapper func(item T) T) Stream[T] {
-	source := make([]T, s.Count())
-
-	for i, v := range s.source {
-		source[i] = mapper(v)
-	}
-
-	return FromSlice(source)
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+
+		return func() (T, bool) {
+			item, ok := next()
+			if ok {
+				item = mapper(item)
+			}
+			return item, ok
+		}
+	})
 }
 
 // Peek returns a stream consisting of the elements of this stream, additionally performing the provided action on each element as elements are consumed from the resulting stream.
 // Play: https://go.dev/play/p/u1VNzHs6cb2
 func (s Stream[T]) Peek(consumer func(item T)) Stream[T] {
-	for _, v := range s.source {
-		consumer(v)
-	}
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+
+		return func() (T, bool) {
+			item, ok := next()
+			if ok {
+				consumer(item)
+			}
+			return item, ok
+		}
+	})
+}
 
-	return s
+// PeekIndexed is like Peek, but the consumer also receives the index of the element, which counts the elements
+// consumed from this stream starting from 0. The index is reset each time a terminal operation runs.
+func (s Stream[T]) PeekIndexed(consumer func(index int, item T)) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next, index := s.iterator(), 0
+
+		return func() (T, bool) {
+			item, ok := next()
+			if ok {
+				consumer(index, item)
+				index++
+			}
+			return item, ok
+		}
+	})
+}
+
+// PeekLimit is like Peek, but the consumer is only performed on the first n elements consumed from the resulting stream,
+// all elements are still passed downstream unchanged. It's useful for spot-checking a big stream.
+func (s Stream[T]) PeekLimit(n int, consumer func(item T)) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+		peeked := 0
+
+		return func() (T, bool) {
+			item, ok := next()
+			if ok && peeked < n {
+				peeked++
+				consumer(item)
+			}
+			return item, ok
+		}
+	})
 }
 
 // Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements of the stream.
@@ -194,45 +481,113 @@ func (s Stream[T]) Skip(n int) Stream[T] {
 		return s
 	}
 
-	source := make([]T, 0)
-	l := len(s.source)
+	if s.pull == nil && s.ctx == nil {
+		if n >= len(s.source) {
+			return FromSlice([]T{})
+		}
+		return FromSlice(s.source[n:])
+	}
+
+	return newStream(s.ctx, func() func() (T, bool) {
+		next, skipped := s.iterator(), false
+
+		return func() (T, bool) {
+			if !skipped {
+				skipped = true
+				for i := 0; i < n; i++ {
+					if _, ok := next(); !ok {
+						break
+					}
+				}
+			}
+			return next()
+		}
+	})
+}
 
-	if n > l {
-		return FromSlice(source)
+// SkipLast returns a stream consisting of the elements of this stream except the last n elements.
+// If this stream contains no more than n elements then an empty stream will be returned.
+// A lazy stream buffers n elements, since it can't know an element is not among the last n until n more are pulled.
+func (s Stream[T]) SkipLast(n int) Stream[T] {
+	if n < 0 {
+		panic("stream.SkipLast: param n should not be negative")
 	}
 
-	source = make([]T, 0, l-n)
-	for i := n; i < l; i++ {
-		source = append(source, s.source[i])
+	if n == 0 {
+		return s
 	}
 
-	return FromSlice(source)
+	if s.pull == nil && s.ctx == nil {
+		if n >= len(s.source) {
+			return FromSlice([]T{})
+		}
+		return FromSlice(s.source[:len(s.source)-n])
+	}
+
+	return newStream(s.ctx, func() func() (T, bool) {
+		// the buffer grows with the pulled elements, so a huge n on a short stream doesn't allocate n elements.
+		next, buffer, pos := s.iterator(), make([]T, 0), 0
+
+		return func() (T, bool) {
+			for len(buffer) < n {
+				v, ok := next()
+				if !ok {
+					var zeroValue T
+					return zeroValue, false
+				}
+				buffer = append(buffer, v)
+			}
+
+			v, ok := next()
+			if !ok {
+				var zeroValue T
+				return zeroValue, false
+			}
+
+			// the oldest buffered element is no longer among the last n.
+			result := buffer[pos]
+			buffer[pos] = v
+			pos = (pos + 1) % n
+
+			return result, true
+		}
+	})
 }
 
 // Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
+// A non-positive maxSize returns an empty stream, nothing is allocated up front for a huge maxSize.
 // Play: https://go.dev/play/p/qsO4aniDcGf
 func (s Stream[T]) Limit(maxSize int) Stream[T] {
-	if s.source == nil {
+	if s.pull == nil && s.ctx == nil {
+		if maxSize <= 0 {
+			return FromSlice([]T{})
+		}
+		if maxSize < len(s.source) {
+			return FromSlice(s.source[:maxSize])
+		}
 		return s
 	}
 
-	if maxSize < 0 {
-		return FromSlice([]T{})
-	}
-
-	source := make([]T, 0, maxSize)
+	return newStream(s.ctx, func() func() (T, bool) {
+		next, count := s.iterator(), 0
 
-	for i := 0; i < len(s.source) && i < maxSize; i++ {
-		source = append(source, s.source[i])
-	}
+		return func() (T, bool) {
+			if count >= maxSize {
+				var zeroValue T
+				return zeroValue, false
+			}
 
-	return FromSlice(source)
+			count++
+			return next()
+		}
+	})
 }
 
 // AllMatch returns whether all elements of this stream match the provided predicate.
 // Play: https://go.dev/play/p/V5TBpVRs-Cx
 func (s Stream[T]) AllMatch(predicate func(item T) bool) bool {
-	for _, v := range s.source {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		if !predicate(v) {
 			return false
 		}
@@ -244,7 +599,8 @@ func (s Stream[T]) AllMatch(predicate func(item T) bool) bool {
 // AnyMatch returns whether any elements of this stream match the provided predicate.
 // Play: https://go.dev/play/p/PTCnWn4OxSn
 func (s Stream[T]) AnyMatch(predicate func(item T) bool) bool {
-	for _, v := range s.source {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		if predicate(v) {
 			return true
 		}
@@ -262,61 +618,264 @@ func (s Stream[T]) NoneMatch(predicate func(item T) bool) bool {
 // ForEach performs an action for each element of this stream.
 // Play: https://go.dev/play/p/Dsm0fPqcidk
 func (s Stream[T]) ForEach(action func(item T)) {
-	for _, v := range s.source {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		action(v)
 	}
 }
 
+// ForEachIndexed performs an action for each element of this stream, the index of the element starts from 0.
+func (s Stream[T]) ForEachIndexed(action func(index int, item T)) {
+	index := 0
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		action(index, v)
+		index++
+	}
+}
+
+// ForEachChunk performs an action for each batch of size elements of this stream in encounter order, eg. to insert
+// 1000 rows at a time. The last batch contains the remaining elements and may be smaller than size.
+// It stops pulling elements and returns the error once action returns an error. Each batch is a new slice,
+// so action can retain it without copying. It's the method form of ProcessBatches, which also checks size.
+func (s Stream[T]) ForEachChunk(size int, action func(batch []T) error) error {
+	return ProcessBatches(s, size, action)
+}
+
+// ForEachWhile performs an action for each element of this stream until the action returns false,
+// the remaining elements are not pulled from the stream.
+func (s Stream[T]) ForEachWhile(action func(item T) bool) {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		if !action(v) {
+			return
+		}
+	}
+}
+
 // Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and returns an Optional describing the reduced value, if any.
 // Play: https://go.dev/play/p/6uzZjq_DJLU
 func (s Stream[T]) Reduce(initial T, accumulator func(a, b T) T) T {
-	for _, v := range s.source {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		initial = accumulator(initial, v)
 	}
 
 	return initial
 }
 
+// ReduceOptional performs a reduction on the elements of this stream, using the first element as the initial value.
+// It returns the reduced value and true, or zero value and false if the stream is empty.
+func (s Stream[T]) ReduceOptional(accumulator func(a, b T) T) (T, bool) {
+	next := s.iterator()
+
+	result, ok := next()
+	if !ok {
+		return result, false
+	}
+
+	for v, ok := next(); ok; v, ok = next() {
+		result = accumulator(result, v)
+	}
+
+	return result, true
+}
+
+// Scan returns a stream consisting of the initial value followed by the running results of the accumulation,
+// which are initial, accumulator(initial, e0), accumulator(accumulator(initial, e0), e1), and so on.
+func (s Stream[T]) Scan(initial T, accumulator func(a, b T) T) Stream[T] {
+	return ScanTo(s, initial, accumulator)
+}
+
+// RunningMin returns a stream consisting of the minimum of the elements seen so far after each element of this stream,
+// eg. [3 5 1 2] => [3 3 1 1]. less reports whether a is less than b, of the equal elements the earlier one is kept.
+func (s Stream[T]) RunningMin(less func(a, b T) bool) Stream[T] {
+	return s.runningExtreme(less)
+}
+
+// RunningMax returns a stream consisting of the maximum of the elements seen so far after each element of this stream,
+// eg. [3 5 1 7] => [3 5 5 7], like a high-water mark. less reports whether a is less than b,
+// of the equal elements the earlier one is kept.
+func (s Stream[T]) RunningMax(less func(a, b T) bool) Stream[T] {
+	return s.runningExtreme(func(a, b T) bool {
+		return less(b, a)
+	})
+}
+
+// runningExtreme emits the running extremum, an element replaces the current one if better(item, current).
+func (s Stream[T]) runningExtreme(better func(a, b T) bool) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next, started := s.iterator(), false
+		var current T
+
+		return func() (T, bool) {
+			item, ok := next()
+			if !ok {
+				return item, false
+			}
+
+			if !started || better(item, current) {
+				started = true
+				current = item
+			}
+
+			return current, true
+		}
+	})
+}
+
 // Count returns the count of elements in the stream.
+// It drains the stream without retaining the elements, slice-backed stream returns the length of slice directly.
 // Play: https://go.dev/play/p/r3koY6y_Xo-
 func (s Stream[T]) Count() int {
-	return len(s.source)
+	if s.pull == nil && s.ctx == nil {
+		return len(s.source)
+	}
+
+	count := 0
+
+	next := s.iterator()
+	for _, ok := next(); ok; _, ok = next() {
+		count++
+	}
+
+	return count
 }
 
-// FindFirst returns the first element of this stream and true, or zero value and false if the stream is empty.
-// Play: https://go.dev/play/p/9xEf0-6C1e3
-func (s Stream[T]) FindFirst() (T, bool) {
-	var result T
+// CountMatch returns the count of elements in the stream which match the predicate, in a single pass without
+// retaining the elements.
+func (s Stream[T]) CountMatch(predicate func(item T) bool) int {
+	count := 0
 
-	if s.source == nil || len(s.source) == 0 {
-		return result, false
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		if predicate(v) {
+			count++
+		}
 	}
 
-	return s.source[0], true
+	return count
+}
+
+// FindFirst returns the first element of this stream and true, or zero value and false if the stream is empty.
+// It pulls only one element, so it's O(1) even for a lazy or infinite stream.
+// Play: https://go.dev/play/p/9xEf0-6C1e3
+func (s Stream[T]) FindFirst() (T, bool) {
+	return s.iterator()()
 }
 
 // FindLast returns the last element of this stream and true, or zero value and false if the stream is empty.
+// It's O(1) for a slice-backed stream, a lazy stream is drained to get its last element, which is O(n).
 // Play: https://go.dev/play/p/WZD2rDAW-2h
 func (s Stream[T]) FindLast() (T, bool) {
 	var result T
+	var found bool
 
-	if s.source == nil || len(s.source) == 0 {
-		return result, false
+	if s.pull == nil && s.ctx == nil {
+		if len(s.source) == 0 {
+			return result, false
+		}
+		return s.source[len(s.source)-1], true
 	}
 
-	return s.source[len(s.source)-1], true
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		result, found = v, true
+	}
+
+	return result, found
+}
+
+// Nth returns the element at the zero-based index of this stream and true, or zero value and false if index is out of range.
+// A negative index counts from the end of the stream, eg. -1 is the last element.
+// For a non-negative index, it stops after consuming index+1 elements.
+func (s Stream[T]) Nth(index int) (T, bool) {
+	var zeroValue T
+
+	next := s.iterator()
+
+	if index >= 0 {
+		for v, ok := next(); ok; v, ok = next() {
+			if index == 0 {
+				return v, true
+			}
+			index--
+		}
+		return zeroValue, false
+	}
+
+	// -math.MinInt overflows, and no stream can hold more than math.MaxInt elements.
+	if index == math.MinInt {
+		return zeroValue, false
+	}
+
+	// keep the last -index elements in a ring buffer, it grows with the pulled elements.
+	size := -index
+	buffer := make([]T, 0)
+	count := 0
+
+	for v, ok := next(); ok; v, ok = next() {
+		if len(buffer) < size {
+			buffer = append(buffer, v)
+		} else {
+			buffer[count%size] = v
+		}
+		count++
+	}
+
+	if count < size {
+		return zeroValue, false
+	}
+
+	return buffer[count%size], true
+}
+
+// First returns the first element of this stream which matches the predicate and true,
+// or zero value and false if no element matches. It stops at the first matched element.
+func (s Stream[T]) First(predicate func(item T) bool) (T, bool) {
+	return s.Filter(predicate).FindFirst()
+}
+
+// Last returns the last element of this stream which matches the predicate and true,
+// or zero value and false if no element matches.
+func (s Stream[T]) Last(predicate func(item T) bool) (T, bool) {
+	return s.Filter(predicate).FindLast()
 }
 
 // Reverse returns a stream whose elements are reverse order of given stream.
+// It's a materializing operation: all elements are pulled into a new slice when the returned stream is consumed,
+// so it needs O(n) extra memory. Use ReverseInPlace to reverse a slice-backed stream without copying.
 // Play: https://go.dev/play/p/A8_zkJnLHm4
 func (s Stream[T]) Reverse() Stream[T] {
-	l := len(s.source)
-	source := make([]T, l)
+	return newStream(s.ctx, func() func() (T, bool) {
+		// ToSlice returns a new slice, so it can be reversed in place.
+		source := s.ToSlice()
+		reverseSlice(source)
 
-	for i := 0; i < l; i++ {
-		source[i] = s.source[l-1-i]
+		return sliceIterator(source)
+	})
+}
+
+// ReverseInPlace reverses the source slice of a slice-backed stream (created by FromSlice or Of) in place immediately,
+// and returns the stream over it, without allocating.
+// Note: it mutates the slice passed to FromSlice, which is visible to the caller and all streams sharing the slice.
+// A lazy stream has no source slice, ReverseInPlace is the same as Reverse for it.
+func (s Stream[T]) ReverseInPlace() Stream[T] {
+	if s.pull != nil {
+		return s.Reverse()
+	}
+
+	reverseSlice(s.source)
+
+	return s
+}
+
+// reverseSlice reverses the elements of slice in place.
+func reverseSlice[T any](slice []T) {
+	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
+		slice[i], slice[j] = slice[j], slice[i]
 	}
-	return FromSlice(source)
 }
 
 // Range returns a stream whose elements are in the range from start(included) to end(excluded) original stream.
@@ -329,63 +888,141 @@ func (s Stream[T]) Range(start, end int) Stream[T] {
 		end = 0
 	}
 	if start >= end {
-		return FromSlice([]T{})
-	}
-
-	source := make([]T, 0)
-
-	if end > len(s.source) {
-		end = len(s.source)
+		return FromSlice([]T{}).WithContext(s.ctx)
 	}
 
-	for i := start; i < end; i++ {
-		source = append(source, s.source[i])
-	}
-
-	return FromSlice(source)
+	return s.Skip(start).Limit(end - start)
 }
 
 // Sorted returns a stream consisting of the elements of this stream, sorted according to the provided less function.
+// The sort is stable, equal elements keep their encounter order.
 // Play: https://go.dev/play/p/XXtng5uonFj
 func (s Stream[T]) Sorted(less func(a, b T) bool) Stream[T] {
-	source := []T{}
-	source = append(source, s.source...)
+	return newStream(s.ctx, func() func() (T, bool) {
+		source := s.ToSlice()
 
-	slice.SortBy(source, less)
+		sort.SliceStable(source, func(i, j int) bool {
+			return less(source[i], source[j])
+		})
 
-	return FromSlice(source)
+		return sliceIterator(source)
+	})
+}
+
+// SortedReverse returns a stream consisting of the elements of this stream, sorted in the reverse order of the provided less function.
+// The sort is stable, equal elements keep their encounter order.
+func (s Stream[T]) SortedReverse(less func(a, b T) bool) Stream[T] {
+	return s.Sorted(func(a, b T) bool {
+		return less(b, a)
+	})
+}
+
+// Shuffle returns a stream consisting of the elements of this stream in a random order, the source is not mutated.
+func (s Stream[T]) Shuffle() Stream[T] {
+	return s.shuffle(func() *rand.Rand {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	})
+}
+
+// ShuffleWithSeed is like Shuffle, but the random number generator is seeded with seed,
+// so the same order is produced at each traversal of the same stream.
+func (s Stream[T]) ShuffleWithSeed(seed int64) Stream[T] {
+	return s.shuffle(func() *rand.Rand {
+		return rand.New(rand.NewSource(seed))
+	})
+}
+
+// shuffle implements Fisher–Yates shuffle, newRand is called at each traversal of the stream.
+func (s Stream[T]) shuffle(newRand func() *rand.Rand) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		source := s.ToSlice()
+
+		r := newRand()
+		for i := len(source) - 1; i > 0; i-- {
+			j := r.Intn(i + 1)
+			source[i], source[j] = source[j], source[i]
+		}
+
+		return sliceIterator(source)
+	})
+}
+
+// Sample returns a stream of at most n elements randomly selected from this stream with reservoir sampling,
+// the stream is traversed in a single pass, so its length does not need to be known in advance.
+func (s Stream[T]) Sample(n int) Stream[T] {
+	return s.sample(n, func() *rand.Rand {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	})
+}
+
+// SampleWithSeed is like Sample, but the random number generator is seeded with seed,
+// so the same elements are selected at each traversal of the same stream.
+func (s Stream[T]) SampleWithSeed(n int, seed int64) Stream[T] {
+	return s.sample(n, func() *rand.Rand {
+		return rand.New(rand.NewSource(seed))
+	})
+}
+
+// sample implements Algorithm R, newRand is called at each traversal of the stream.
+func (s Stream[T]) sample(n int, newRand func() *rand.Rand) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		reservoir := []T{}
+		if n <= 0 {
+			return sliceIterator(reservoir)
+		}
+
+		r := newRand()
+		count := 0
+
+		next := s.iterator()
+		for v, ok := next(); ok; v, ok = next() {
+			if count < n {
+				reservoir = append(reservoir, v)
+			} else if j := r.Intn(count + 1); j < n {
+				reservoir[j] = v
+			}
+			count++
+		}
+
+		return sliceIterator(reservoir)
+	})
 }
 
 // Max returns the maximum element of this stream according to the provided less function.
-// less: a > b
+// less: a > b, it reports whether a should replace the current maximum b.
+// The first element is used as the initial maximum, so the result never depends on the zero value of T.
 // Play: https://go.dev/play/p/fm-1KOPtGzn
 func (s Stream[T]) Max(less func(a, b T) bool) (T, bool) {
-	var max T
+	next := s.iterator()
 
-	if len(s.source) == 0 {
+	max, ok := next()
+	if !ok {
 		return max, false
 	}
 
-	for i, v := range s.source {
-		if less(v, max) || i == 0 {
+	for v, ok := next(); ok; v, ok = next() {
+		if less(v, max) {
 			max = v
 		}
 	}
+
 	return max, true
 }
 
 // Min returns the minimum element of this stream according to the provided less function.
-// less: a < b
+// less: a < b, it reports whether a should replace the current minimum b.
+// The first element is used as the initial minimum, so the result never depends on the zero value of T.
 // Play: https://go.dev/play/p/vZfIDgGNRe_0
 func (s Stream[T]) Min(less func(a, b T) bool) (T, bool) {
-	var min T
+	next := s.iterator()
 
-	if len(s.source) == 0 {
+	min, ok := next()
+	if !ok {
 		return min, false
 	}
 
-	for i, v := range s.source {
-		if less(v, min) || i == 0 {
+	for v, ok := next(); ok; v, ok = next() {
+		if less(v, min) {
 			min = v
 		}
 	}
@@ -396,10 +1033,29 @@ func (s Stream[T]) Min(less func(a, b T) bool) (T, bool) {
 // IndexOf returns the index of the first occurrence of the specified element in this stream, or -1 if this stream does not contain the element.
 // Play: https://go.dev/play/p/tBV5Nc-XDX2
 func (s Stream[T]) IndexOf(target T, equal func(a, b T) bool) int {
-	for i, v := range s.source {
+	index := 0
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		if equal(v, target) {
-			return i
+			return index
 		}
+		index++
+	}
+	return -1
+}
+
+// IndexOfBy returns the index of the first element of this stream which matches the predicate, or -1 if no element matches.
+// It stops at the first matched element.
+func (s Stream[T]) IndexOfBy(predicate func(item T) bool) int {
+	index := 0
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		if predicate(v) {
+			return index
+		}
+		index++
 	}
 	return -1
 }
@@ -407,8 +1063,9 @@ func (s Stream[T]) IndexOf(target T, equal func(a, b T) bool) int {
 // LastIndexOf returns the index of the last occurrence of the specified element in this stream, or -1 if this stream does not contain the element.
 // Play: https://go.dev/play/p/CjeoNw2eac_G
 func (s Stream[T]) LastIndexOf(target T, equal func(a, b T) bool) int {
-	for i := len(s.source) - 1; i >= 0; i-- {
-		if equal(s.source[i], target) {
+	source := s.ToSlice()
+	for i := len(source) - 1; i >= 0; i-- {
+		if equal(source[i], target) {
 			return i
 		}
 	}
@@ -416,16 +1073,933 @@ func (s Stream[T]) LastIndexOf(target T, equal func(a, b T) bool) int {
 }
 
 // ToSlice return the elements in the stream.
+// The returned slice is always a fresh copy, modifying it doesn't affect the stream.
 // Play: https://go.dev/play/p/jI6_iZZuVFE
 func (s Stream[T]) ToSlice() []T {
-	return s.source
+	if s.pull == nil && s.ctx == nil {
+		return append(make([]T, 0, len(s.source)), s.source...)
+	}
+
+	source := make([]T, 0)
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		source = append(source, v)
+	}
+
+	return source
+}
+
+// ToSliceN return at most the first n elements in the stream, it stops pulling elements once n elements are collected.
+// A non-positive n returns an empty slice.
+func (s Stream[T]) ToSliceN(n int) []T {
+	result := make([]T, 0)
+	if n <= 0 {
+		return result
+	}
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		result = append(result, v)
+		if len(result) == n {
+			break
+		}
+	}
+
+	return result
+}
+
+// ToChannel returns an unbuffered channel which receives the elements of this stream in order.
+// The channel is closed when all elements have been sent or the ctx is done, so the sending goroutine
+// will not leak even if the consumer stops reading. A nil ctx is treated as context.Background().
+func (s Stream[T]) ToChannel(ctx context.Context) <-chan T {
+	return s.ToChannelWithBuffer(ctx, 0)
 }
 
+// ToChannelWithBuffer is like ToChannel, but the returned channel has the given buffer size.
+func (s Stream[T]) ToChannelWithBuffer(ctx context.Context, size int) <-chan T {
+	if size < 0 {
+		panic("stream.ToChannelWithBuffer: param size should not be negative")
+	}
+
+	// a nil ctx would panic in the sending goroutine, where the caller can't recover it.
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ch := make(chan T, size)
+
+	go func() {
+		defer close(ch)
+
+		next := s.iterator()
+		for v, ok := next(); ok; v, ok = next() {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- v:
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Drain sends every element of the stream into out in order, blocking whenever out is full,
+// and returns once the stream is exhausted. Unlike ToChannel, the channel is owned by the caller
+// and is left open. If the stream has a ctx (see WithContext), Drain also returns once the ctx is done,
+// even if it is blocked on sending.
+func (s Stream[T]) Drain(out chan<- T) {
+	var done <-chan struct{}
+	if s.ctx != nil {
+		done = s.ctx.Done()
+	}
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		select {
+		case <-done:
+			return
+		case out <- v:
+		}
+	}
+}
+
+// ToMap collects the elements of the stream into a map, the key and value of each entry are returned by mapper.
+// If several elements have the same key, the last one wins.
 func ToMap[T any, K comparable, V any](s Stream[T], mapper func(item T) (K, V)) map[K]V {
 	result := map[K]V{}
-	for _, v := range s.source {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
 		key, value := mapper(v)
 		result[key] = value
 	}
 	return result
 }
+
+// Chunk returns a stream whose elements are slices of at most size elements of the given stream.
+// The last chunk may contain fewer than size elements. size should be positive.
+func Chunk[T any](s Stream[T], size int) Stream[[]T] {
+	if size <= 0 {
+		panic("stream.Chunk: param size should be positive")
+	}
+
+	return newStream(s.ctx, func() func() ([]T, bool) {
+		next := s.iterator()
+
+		return func() ([]T, bool) {
+			chunk := make([]T, 0, capacityHint(size))
+			for len(chunk) < size {
+				item, ok := next()
+				if !ok {
+					break
+				}
+				chunk = append(chunk, item)
+			}
+
+			return chunk, len(chunk) > 0
+		}
+	})
+}
+
+// Window returns a stream whose elements are sliding windows of size elements of the given stream,
+// each window starts step elements after the previous one. If fewer than size elements remain at the end,
+// the partial window is dropped. both size and step should be positive.
+// eg. Window(FromSlice([]int{1, 2, 3, 4}), 3, 1) => [[1 2 3] [2 3 4]]
+func Window[T any](s Stream[T], size, step int) Stream[[]T] {
+	if size <= 0 {
+		panic("stream.Window: param size should be positive")
+	} else if step <= 0 {
+		panic("stream.Window: param step should be positive")
+	}
+
+	return newStream(s.ctx, func() func() ([]T, bool) {
+		next := s.iterator()
+		buffer := make([]T, 0, capacityHint(size))
+		exhausted := false
+
+		return func() ([]T, bool) {
+			if len(buffer) == size {
+				// drop the leading elements of the previous window.
+				if step < size {
+					buffer = append(buffer[:0], buffer[step:]...)
+				} else {
+					buffer = buffer[:0]
+					for i := 0; i < step-size && !exhausted; i++ {
+						_, ok := next()
+						exhausted = !ok
+					}
+				}
+			}
+
+			for len(buffer) < size && !exhausted {
+				item, ok := next()
+				if !ok {
+					exhausted = true
+					break
+				}
+				buffer = append(buffer, item)
+			}
+
+			if len(buffer) < size {
+				return nil, false
+			}
+
+			window := make([]T, size)
+			copy(window, buffer)
+			return window, true
+		}
+	})
+}
+
+// Pair is a simple struct which holds two values, it's the element type of a zipped stream.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip returns a stream whose elements are pairs of the elements at the same position of stream a and b.
+// The length of the returned stream is the length of the shorter one.
+func Zip[A any, B any](a Stream[A], b Stream[B]) Stream[Pair[A, B]] {
+	return ZipWith(a, b, func(first A, second B) Pair[A, B] {
+		return Pair[A, B]{First: first, Second: second}
+	})
+}
+
+// Indexed is an element of a stream paired with its position, it's the element type of the stream returned by WithIndex.
+type Indexed[T any] struct {
+	Index int
+	Value T
+}
+
+// WithIndex returns a stream whose elements are the elements of the stream paired with their index starting from 0,
+// so the operations depending on the position can be expressed in the pipeline.
+func WithIndex[T any](s Stream[T]) Stream[Indexed[T]] {
+	return newStream(s.ctx, func() func() (Indexed[T], bool) {
+		next, index := s.iterator(), 0
+
+		return func() (Indexed[T], bool) {
+			item, ok := next()
+			if !ok {
+				return Indexed[T]{}, false
+			}
+
+			index++
+			return Indexed[T]{Index: index - 1, Value: item}, true
+		}
+	})
+}
+
+// Unzip is the inverse of Zip, it splits a stream of pairs into a slice of the first values and a slice of the second values.
+func Unzip[A any, B any](s Stream[Pair[A, B]]) ([]A, []B) {
+	firsts, seconds := make([]A, 0), make([]B, 0)
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		firsts = append(firsts, v.First)
+		seconds = append(seconds, v.Second)
+	}
+
+	return firsts, seconds
+}
+
+// ZipWith returns a stream whose elements are the results of applying combiner to the elements at the same position of stream a and b.
+// The length of the returned stream is the length of the shorter one.
+func ZipWith[A any, B any, R any](a Stream[A], b Stream[B], combiner func(A, B) R) Stream[R] {
+	return newStream(a.ctx, func() func() (R, bool) {
+		nextA, nextB := a.iterator(), b.iterator()
+
+		return func() (R, bool) {
+			itemA, ok := nextA()
+			if !ok {
+				var zeroValue R
+				return zeroValue, false
+			}
+
+			itemB, ok := nextB()
+			if !ok {
+				var zeroValue R
+				return zeroValue, false
+			}
+
+			return combiner(itemA, itemB), true
+		}
+	})
+}
+
+// Sum returns the sum of all elements of the number stream.
+func Sum[T constraints.Integer | constraints.Float](s Stream[T]) T {
+	var sum T
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		sum += v
+	}
+
+	return sum
+}
+
+// Average returns the average of all elements of the number stream, returns 0 if the stream is empty.
+func Average[T constraints.Integer | constraints.Float](s Stream[T]) float64 {
+	var sum float64
+	var count int
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		sum += float64(v)
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return sum / float64(count)
+}
+
+// Stats is the summary statistics of a number stream, returned by Summarize.
+type Stats[T constraints.Integer | constraints.Float] struct {
+	Count   int
+	Sum     T
+	Min     T
+	Max     T
+	Average float64
+	// Empty reports whether the stream has no element, Min, Max and Average are zero values if it is true.
+	Empty bool
+}
+
+// Summarize returns the count, sum, min, max and average of all elements of the number stream in a single traversal.
+func Summarize[T constraints.Integer | constraints.Float](s Stream[T]) Stats[T] {
+	stats := Stats[T]{Empty: true}
+
+	var sum float64
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		if stats.Empty || v < stats.Min {
+			stats.Min = v
+		}
+		if stats.Empty || v > stats.Max {
+			stats.Max = v
+		}
+
+		stats.Empty = false
+		stats.Count++
+		stats.Sum += v
+		sum += float64(v)
+	}
+
+	if stats.Count > 0 {
+		stats.Average = sum / float64(stats.Count)
+	}
+
+	return stats
+}
+
+// DistinctComparable returns a stream that removes the duplicated items of a stream whose element type is comparable.
+// It compares elements directly instead of encoding them like Distinct, so it's much faster.
+func DistinctComparable[T comparable](s Stream[T]) Stream[T] {
+	return DistinctBy(s, func(item T) T {
+		return item
+	})
+}
+
+// DistinctBy returns a stream that keeps the first item for each key returned by keyFn, the encounter order is preserved.
+func DistinctBy[T any, K comparable](s Stream[T], keyFn func(item T) K) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+		distinct := map[K]struct{}{}
+
+		return func() (T, bool) {
+			for item, ok := next(); ok; item, ok = next() {
+				key := keyFn(item)
+				if _, ok := distinct[key]; !ok {
+					distinct[key] = struct{}{}
+					return item, true
+				}
+			}
+
+			var zeroValue T
+			return zeroValue, false
+		}
+	})
+}
+
+// Fold performs a reduction on the elements of the stream into an accumulator of type R, which
+// can be different from the element type.
+func Fold[T any, R any](s Stream[T], initial R, accumulator func(acc R, item T) R) R {
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		initial = accumulator(initial, v)
+	}
+
+	return initial
+}
+
+// Reducer is a reusable reduction of the elements of type T into a result of type R.
+type Reducer[T any, R any] interface {
+	// Supplier returns the initial result, it's called once per reduction.
+	Supplier() R
+
+	// Accumulator folds item into the result and returns the new result.
+	Accumulator(result R, item T) R
+}
+
+type funcReducer[T any, R any] struct {
+	supplier    func() R
+	accumulator func(R, T) R
+}
+
+func (r funcReducer[T, R]) Supplier() R {
+	return r.supplier()
+}
+
+func (r funcReducer[T, R]) Accumulator(result R, item T) R {
+	return r.accumulator(result, item)
+}
+
+// NewReducer returns a Reducer built from the supplier and accumulator functions.
+func NewReducer[T any, R any](supplier func() R, accumulator func(result R, item T) R) Reducer[T, R] {
+	return funcReducer[T, R]{supplier: supplier, accumulator: accumulator}
+}
+
+// CollectWith reduces the elements of the stream with the reducer in one pass.
+func CollectWith[T any, R any](s Stream[T], reducer Reducer[T, R]) R {
+	return Fold(s, reducer.Supplier(), reducer.Accumulator)
+}
+
+// ScanTo is like Stream.Scan, but the accumulated value can be of a different type from the element type.
+func ScanTo[T any, R any](s Stream[T], initial R, accumulator func(acc R, item T) R) Stream[R] {
+	return newStream(s.ctx, func() func() (R, bool) {
+		next, acc, started := s.iterator(), initial, false
+
+		return func() (R, bool) {
+			if !started {
+				started = true
+				return acc, true
+			}
+
+			item, ok := next()
+			if !ok {
+				var zeroValue R
+				return zeroValue, false
+			}
+
+			acc = accumulator(acc, item)
+			return acc, true
+		}
+	})
+}
+
+// Associate returns a map containing the key-value pairs returned by transform for each element of the stream.
+// If several elements have the same key, the last one wins.
+// It's the same as ToMap, the name follows kotlin's `associate`.
+func Associate[T any, K comparable, V any](s Stream[T], transform func(item T) (K, V)) map[K]V {
+	return ToMap(s, transform)
+}
+
+// Join concatenates the elements of the string stream, the sep is placed between elements.
+func Join(s Stream[string], sep string) string {
+	return JoinWith(s, sep, func(item string) string {
+		return item
+	})
+}
+
+// JoinWith concatenates the string representations returned by toString of the elements of the stream,
+// the sep is placed between elements.
+func JoinWith[T any](s Stream[T], sep string, toString func(item T) string) string {
+	var builder strings.Builder
+
+	first := true
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		if !first {
+			builder.WriteString(sep)
+		}
+		first = false
+		builder.WriteString(toString(v))
+	}
+
+	return builder.String()
+}
+
+// Flatten returns a stream consisting of the elements of all inner slices of the given stream in order.
+// It's the reverse operation of Chunk, the inner slices are not materialized into one slice.
+func Flatten[T any](s Stream[[]T]) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+		var current []T
+
+		return func() (T, bool) {
+			for len(current) == 0 {
+				inner, ok := next()
+				if !ok {
+					var zeroValue T
+					return zeroValue, false
+				}
+				current = inner
+			}
+
+			item := current[0]
+			current = current[1:]
+			return item, true
+		}
+	})
+}
+
+// Contain checks if the target is an element of the stream.
+func Contain[T comparable](s Stream[T], target T) bool {
+	return s.AnyMatch(func(item T) bool {
+		return item == target
+	})
+}
+
+// MaxBy returns the element of the stream with the maximum key returned by keyFn and true,
+// or zero value and false if the stream is empty. If several elements have the maximum key, the first one is returned.
+func MaxBy[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K) (T, bool) {
+	return extremumBy(s, keyFn, func(a, b K) bool { return a > b })
+}
+
+// MinBy returns the element of the stream with the minimum key returned by keyFn and true,
+// or zero value and false if the stream is empty. If several elements have the minimum key, the first one is returned.
+func MinBy[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K) (T, bool) {
+	return extremumBy(s, keyFn, func(a, b K) bool { return a < b })
+}
+
+// extremumBy returns the first element whose key is not replaced by any other key according to the better function.
+func extremumBy[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K, better func(a, b K) bool) (T, bool) {
+	next := s.iterator()
+
+	result, ok := next()
+	if !ok {
+		return result, false
+	}
+
+	resultKey := keyFn(result)
+	for v, ok := next(); ok; v, ok = next() {
+		if key := keyFn(v); better(key, resultKey) {
+			result, resultKey = v, key
+		}
+	}
+
+	return result, true
+}
+
+// SortedBy returns a stream consisting of the elements of the stream, sorted in ascending order of the keys returned by keyFn.
+// The sort is stable, elements with equal keys keep their encounter order.
+func SortedBy[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K) Stream[T] {
+	return sortedByKey(s, keyFn, func(a, b K) bool { return a < b })
+}
+
+// SortedByDesc is like SortedBy, but sorts in descending order of the keys.
+func SortedByDesc[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K) Stream[T] {
+	return sortedByKey(s, keyFn, func(a, b K) bool { return a > b })
+}
+
+// sortedByKey stable sorts the elements of stream by their keys, the key of each element is computed only once.
+func sortedByKey[T any, K constraints.Ordered](s Stream[T], keyFn func(item T) K, less func(a, b K) bool) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		source := s.ToSlice()
+
+		pairs := make([]Pair[K, T], len(source))
+		for i, v := range source {
+			pairs[i] = Pair[K, T]{First: keyFn(v), Second: v}
+		}
+
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return less(pairs[i].First, pairs[j].First)
+		})
+
+		sorted := make([]T, len(pairs))
+		for i, pair := range pairs {
+			sorted[i] = pair.Second
+		}
+
+		return sliceIterator(sorted)
+	})
+}
+
+// ToSet collects the elements of the stream into a set, the duplicated elements are removed.
+// Note: the encounter order is lost since the set is a map.
+func ToSet[T comparable](s Stream[T]) map[T]struct{} {
+	result := map[T]struct{}{}
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		result[v] = struct{}{}
+	}
+
+	return result
+}
+
+// Frequency returns the number of occurrences of each distinct element of the stream, counted in one pass.
+// It's like GroupBy then len of each group, but without collecting the elements of the groups.
+// eg. Frequency(Of("a", "b", "a")) => map[a:2 b:1]
+func Frequency[T comparable](s Stream[T]) map[T]int {
+	result := map[T]int{}
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		result[v]++
+	}
+
+	return result
+}
+
+// Interleave returns a stream which takes one element from each of the streams in turn, the exhausted streams are skipped
+// until all streams are drained. eg. Interleave(Of(1, 2, 3), Of(4), Of(5, 6)) => [1 4 5 2 6 3]
+func Interleave[T any](streams ...Stream[T]) Stream[T] {
+	return newStream(firstContext(streams), func() func() (T, bool) {
+		iterators := make([]func() (T, bool), len(streams))
+		for i, s := range streams {
+			iterators[i] = s.iterator()
+		}
+
+		index := 0
+
+		return func() (T, bool) {
+			for len(iterators) > 0 {
+				if index >= len(iterators) {
+					index = 0
+				}
+
+				item, ok := iterators[index]()
+				if ok {
+					index++
+					return item, true
+				}
+
+				// remove the exhausted iterator, the next one moves to current index.
+				iterators = append(iterators[:index], iterators[index+1:]...)
+			}
+
+			var zeroValue T
+			return zeroValue, false
+		}
+	})
+}
+
+// Tee returns n streams which yield the same elements as s, the upstream pipeline is evaluated only once.
+// The elements pulled from s are buffered, so that each returned stream can be consumed independently.
+func Tee[T any](s Stream[T], n int) []Stream[T] {
+	if n < 0 {
+		panic("stream.Tee: param n should not be negative")
+	}
+
+	var mu sync.Mutex
+	var buffer []T
+	var next func() (T, bool)
+	done := false
+
+	// get returns the element at index i of s, pulling from s if it is not buffered yet.
+	get := func(i int) (T, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for i >= len(buffer) && !done {
+			if next == nil {
+				next = s.iterator()
+			}
+			item, ok := next()
+			if !ok {
+				done = true
+				break
+			}
+			buffer = append(buffer, item)
+		}
+
+		if i < len(buffer) {
+			return buffer[i], true
+		}
+
+		var zeroValue T
+		return zeroValue, false
+	}
+
+	result := make([]Stream[T], n)
+	for i := range result {
+		result[i] = newStream(s.ctx, func() func() (T, bool) {
+			index := 0
+			return func() (T, bool) {
+				item, ok := get(index)
+				if ok {
+					index++
+				}
+				return item, ok
+			}
+		})
+	}
+
+	return result
+}
+
+// GroupCount is the key of a group and the count of its elements, returned by TopGroups.
+type GroupCount[K comparable] struct {
+	Key   K
+	Count int
+}
+
+// TopGroups groups the elements of stream by keyFn, and returns the n groups with the most elements, sorted by count
+// in descending order. The groups with the same count keep the order of their first elements in the stream.
+// If n is negative or greater than the number of groups, all groups are returned.
+func TopGroups[T any, K comparable](s Stream[T], keyFn func(item T) K, n int) []GroupCount[K] {
+	groups := make([]GroupCount[K], 0)
+	indexes := make(map[K]int)
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		key := keyFn(v)
+		if i, ok := indexes[key]; ok {
+			groups[i].Count++
+		} else {
+			indexes[key] = len(groups)
+			groups = append(groups, GroupCount[K]{Key: key, Count: 1})
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+
+	if n >= 0 && n < len(groups) {
+		groups = groups[:n]
+	}
+
+	return groups
+}
+
+// ProcessBatches consumes the stream in batches of size elements and calls fn with each batch in encounter order.
+// The last batch contains the remaining elements and may be smaller than size, it's not called for an empty stream.
+// It stops pulling elements and returns the error once fn returns an error. Each batch is a new slice, so fn can retain it.
+func ProcessBatches[T any](s Stream[T], size int, fn func(batch []T) error) error {
+	if size <= 0 {
+		panic("stream.ProcessBatches: param size should be positive")
+	}
+
+	batch := make([]T, 0, capacityHint(size))
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		batch = append(batch, v)
+		if len(batch) == size {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]T, 0, capacityHint(size))
+		}
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+
+	return nil
+}
+
+// MapE applies mapper to each element of the stream and returns the results in order.
+// It stops pulling elements and returns nil and the error once mapper returns an error.
+func MapE[T any, R any](s Stream[T], mapper func(item T) (R, error)) ([]R, error) {
+	result := make([]R, 0)
+
+	next := s.iterator()
+	for v, ok := next(); ok; v, ok = next() {
+		r, err := mapper(v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// MapParallel returns a stream consisting of the results of applying mapper to the elements of the stream,
+// the mapper calls run concurrently in up to concurrency goroutines, but the results keep the order of the elements.
+// The elements are pulled and mapped in batches of concurrency elements when the returned stream is consumed,
+// so a batch is ready only after its slowest mapper call, and no goroutine outlives the pull even if the
+// downstream stops early. If concurrency <= 0, runtime.GOMAXPROCS(0) is used.
+// A panic in mapper is propagated to the goroutine consuming the stream.
+func MapParallel[T any, R any](s Stream[T], concurrency int, mapper func(item T) R) Stream[R] {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	return newStream(s.ctx, func() func() (R, bool) {
+		next := s.iterator()
+		batch := make([]T, 0, capacityHint(concurrency))
+		results := make([]R, 0, capacityHint(concurrency))
+		pos, exhausted := 0, false
+
+		return func() (R, bool) {
+			if pos == len(results) && !exhausted {
+				batch, results, pos = batch[:0], results[:0], 0
+				for len(batch) < concurrency {
+					v, ok := next()
+					if !ok {
+						exhausted = true
+						break
+					}
+					batch = append(batch, v)
+				}
+
+				if cap(results) < len(batch) {
+					results = make([]R, len(batch))
+				}
+				results = results[:len(batch)]
+				mapParallel(batch, results, mapper)
+			}
+
+			if pos == len(results) {
+				var zeroValue R
+				return zeroValue, false
+			}
+
+			pos++
+			return results[pos-1], true
+		}
+	})
+}
+
+// mapParallel stores mapper(items[i]) into results[i], each item is mapped in its own goroutine.
+func mapParallel[T any, R any](items []T, results []R, mapper func(item T) R) {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var panicValue any
+
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					once.Do(func() {
+						panicValue = r
+					})
+				}
+			}()
+
+			results[i] = mapper(items[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	if panicValue != nil {
+		panic(panicValue)
+	}
+}
+
+// Compact returns a stream that removes the elements equal to the zero value of T, eg. "", 0, false and nil.
+// For a pointer element, only the nil pointer is removed, a pointer to a zero value is kept.
+// An interface element type satisfies comparable only since Go 1.20 (the module targets Go 1.18), with it
+// only the nil interface is removed, an interface holding a zero value or a typed nil pointer is kept.
+func Compact[T comparable](s Stream[T]) Stream[T] {
+	var zeroValue T
+
+	return s.Filter(func(item T) bool {
+		return item != zeroValue
+	})
+}
+
+// Dedup returns a stream that collapses the consecutive equal elements into one, like the unix uniq command.
+// Unlike DistinctComparable, the equal elements which are not adjacent are kept, eg. Dedup(Of(1, 1, 2, 1)) => [1 2 1].
+// It doesn't use a map, so it is cheaper for the sorted stream.
+func Dedup[T comparable](s Stream[T]) Stream[T] {
+	return DedupBy(s, func(item T) T {
+		return item
+	})
+}
+
+// DedupBy returns a stream that collapses the consecutive elements with the same key into the first one of them.
+func DedupBy[T any, K comparable](s Stream[T], keyFn func(item T) K) Stream[T] {
+	return newStream(s.ctx, func() func() (T, bool) {
+		next := s.iterator()
+
+		var lastKey K
+		started := false
+
+		return func() (T, bool) {
+			for item, ok := next(); ok; item, ok = next() {
+				key := keyFn(item)
+				if !started || key != lastKey {
+					started = true
+					lastKey = key
+					return item, true
+				}
+			}
+
+			var zeroValue T
+			return zeroValue, false
+		}
+	})
+}
+
+// GroupAdjacent returns a stream of the groups of consecutive elements which have the same key returned by keyFn,
+// a new group starts whenever the key changes, eg. the log lines grouped by minute. Unlike GroupBy, only the current
+// group is kept in memory and the order of the elements is preserved, the same key may appear in several groups.
+func GroupAdjacent[T any, K comparable](s Stream[T], keyFn func(item T) K) Stream[[]T] {
+	return newStream(s.ctx, func() func() ([]T, bool) {
+		next := s.iterator()
+
+		var pending T
+		var pendingKey K
+		hasPending := false
+
+		return func() ([]T, bool) {
+			if !hasPending {
+				item, ok := next()
+				if !ok {
+					return nil, false
+				}
+				pending, pendingKey, hasPending = item, keyFn(item), true
+			}
+
+			group, key := []T{pending}, pendingKey
+			hasPending = false
+
+			for item, ok := next(); ok; item, ok = next() {
+				itemKey := keyFn(item)
+				if itemKey != key {
+					pending, pendingKey, hasPending = item, itemKey, true
+					break
+				}
+				group = append(group, item)
+			}
+
+			return group, true
+		}
+	})
+}
+
+// Equal reports whether the two streams have the same elements in the same order.
+// It stops pulling elements at the first difference, or once one of the streams is exhausted.
+func Equal[T comparable](a, b Stream[T]) bool {
+	return EqualBy(a, b, func(x, y T) bool {
+		return x == y
+	})
+}
+
+// EqualBy is like Equal, but two elements are equal if eq returns true for them.
+// Slice-backed streams of different lengths are reported unequal without comparing any element.
+func EqualBy[T any](a, b Stream[T], eq func(x, y T) bool) bool {
+	if a.pull == nil && a.ctx == nil && b.pull == nil && b.ctx == nil && len(a.source) != len(b.source) {
+		return false
+	}
+
+	nextA, nextB := a.iterator(), b.iterator()
+	for {
+		x, okA := nextA()
+		y, okB := nextB()
+
+		if okA != okB {
+			return false
+		}
+		if !okA {
+			return true
+		}
+		if !eq(x, y) {
+			return false
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package stream
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 func ExampleOf() {
@@ -76,6 +80,55 @@ func ExampleGenerate() {
 	// [1 2 3]
 }
 
+func ExampleGenerateN() {
+	s := GenerateN(4, func(index int) string {
+		return fmt.Sprint("item", index)
+	})
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [item0 item1 item2 item3]
+}
+
+func ExampleIterate() {
+	s := Iterate(1, func(n int) int { return n * 2 }).Limit(5)
+
+	data := s.ToSlice()
+
+	fmt.Println(data)
+
+	// Output:
+	// [1 2 4 8 16]
+}
+
+func ExampleEmpty() {
+	s := Empty[int]()
+
+	fmt.Println(s.Count())
+
+	// Output:
+	// 0
+}
+
+func ExampleRepeat() {
+	s := Repeat("a", 3)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [a a a]
+}
+
+func ExampleRepeatInfinite() {
+	s := RepeatInfinite(0).Limit(3)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [0 0 0]
+}
+
 func ExampleConcat() {
 	s1 := FromSlice([]int{1, 2, 3})
 	s2 := FromSlice([]int{4, 5, 6})
@@ -90,6 +143,52 @@ func ExampleConcat() {
 	// [1 2 3 4 5 6]
 }
 
+func ExampleStream_Append() {
+	original := FromSlice([]int{1, 2})
+
+	s := original.Append(3, 4)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 2 3 4]
+}
+
+func ExampleStream_Prepend() {
+	original := FromSlice([]int{3, 4})
+
+	s := original.Prepend(1, 2)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 2 3 4]
+}
+
+func ExampleStream_Intersperse() {
+	original := FromSlice([]int{1, 2, 3})
+
+	s := original.Intersperse(0)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 0 2 0 3]
+}
+
+func ExampleStream_Cache() {
+	original := FromSlice([]int{1, 2, 3}).Map(func(n int) int {
+		return n * 2
+	}).Cache()
+
+	fmt.Println(original.Count())
+	fmt.Println(Sum(original))
+
+	// Output:
+	// 3
+	// 12
+}
+
 func ExampleStream_Distinct() {
 	original := FromSlice([]int{1, 2, 2, 3, 3, 3})
 	distinct := original.Distinct()
@@ -105,6 +204,17 @@ func ExampleStream_Distinct() {
 	// [1 2 3]
 }
 
+func ExampleStream_DistinctByHash() {
+	original := FromSlice([]string{"a", "A", "b", "B", "c"})
+
+	distinct := original.DistinctByHash(strings.ToLower)
+
+	fmt.Println(distinct.ToSlice())
+
+	// Output:
+	// [a b c]
+}
+
 func ExampleStream_Filter() {
 	original := FromSlice([]int{1, 2, 3, 4, 5})
 
@@ -153,6 +263,38 @@ func ExampleStream_Peek() {
 	// [value1 value2 value3]
 }
 
+func ExampleStream_PeekIndexed() {
+	s := FromSlice([]int{3, 8, 5}).PeekIndexed(func(index, n int) {
+		fmt.Printf("element %d: %d\n", index, n)
+	}).Filter(func(n int) bool {
+		return n > 4
+	})
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// element 0: 3
+	// element 1: 8
+	// element 2: 5
+	// [8 5]
+}
+
+func ExampleStream_PeekLimit() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	data := []string{}
+	peekStream := original.PeekLimit(2, func(n int) {
+		data = append(data, fmt.Sprint("value", n))
+	})
+
+	fmt.Println(peekStream.ToSlice())
+	fmt.Println(data)
+
+	// Output:
+	// [1 2 3 4 5]
+	// [value1 value2]
+}
+
 func ExampleStream_Skip() {
 	original := FromSlice([]int{1, 2, 3, 4})
 
@@ -173,6 +315,15 @@ func ExampleStream_Skip() {
 	// []
 }
 
+func ExampleStream_SkipLast() {
+	s := FromSlice([]string{"header", "row1", "row2", "trailer"})
+
+	fmt.Println(s.Skip(1).SkipLast(1).ToSlice())
+
+	// Output:
+	// [row1 row2]
+}
+
 func ExampleStream_Limit() {
 	original := FromSlice([]int{1, 2, 3, 4})
 
@@ -193,6 +344,17 @@ func ExampleStream_Limit() {
 	// [1 2 3 4]
 }
 
+func ExampleChunk() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	chunks := Chunk(original, 2)
+
+	fmt.Println(chunks.ToSlice())
+
+	// Output:
+	// [[1 2] [3 4] [5]]
+}
+
 func ExampleStream_AllMatch() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -264,6 +426,35 @@ func ExampleStream_ForEach() {
 	// 6
 }
 
+func ExampleStream_ForEachIndexed() {
+	original := FromSlice([]string{"a", "b", "c"})
+
+	original.ForEachIndexed(func(index int, item string) {
+		fmt.Println(index, item)
+	})
+
+	// Output:
+	// 0 a
+	// 1 b
+	// 2 c
+}
+
+func ExampleStream_ForEachWhile() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	original.ForEachWhile(func(item int) bool {
+		if item > 2 {
+			return false
+		}
+		fmt.Println(item)
+		return true
+	})
+
+	// Output:
+	// 1
+	// 2
+}
+
 func ExampleStream_Reduce() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -277,6 +468,52 @@ func ExampleStream_Reduce() {
 	// 6
 }
 
+func ExampleStream_ReduceOptional() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result, ok := original.ReduceOptional(func(a, b int) int {
+		return a * b
+	})
+
+	fmt.Println(result, ok)
+
+	_, ok = FromSlice([]int{}).ReduceOptional(func(a, b int) int {
+		return a * b
+	})
+
+	fmt.Println(ok)
+
+	// Output:
+	// 6 true
+	// false
+}
+
+func ExampleStream_RunningMax() {
+	prices := FromSlice([]int{10, 12, 11, 15, 9})
+
+	less := func(a, b int) bool { return a < b }
+
+	fmt.Println(prices.RunningMax(less).ToSlice())
+	fmt.Println(prices.RunningMin(less).ToSlice())
+
+	// Output:
+	// [10 12 12 15 15]
+	// [10 10 10 10 9]
+}
+
+func ExampleStream_Scan() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result := original.Scan(0, func(a, b int) int {
+		return a + b
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [0 1 3 6]
+}
+
 func ExampleStream_FindFirst() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -303,6 +540,63 @@ func ExampleStream_FindLast() {
 	// true
 }
 
+func ExampleStream_CountMatch() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	count := original.CountMatch(func(item int) bool {
+		return item%2 == 1
+	})
+
+	fmt.Println(count)
+
+	// Output:
+	// 3
+}
+
+func ExampleStream_Nth() {
+	original := FromSlice([]int{10, 20, 30, 40})
+
+	second, ok := original.Nth(1)
+	fmt.Println(second, ok)
+
+	last, ok := original.Nth(-1)
+	fmt.Println(last, ok)
+
+	_, ok = original.Nth(4)
+	fmt.Println(ok)
+
+	// Output:
+	// 20 true
+	// 40 true
+	// false
+}
+
+func ExampleStream_First() {
+	original := FromSlice([]int{1, 2, 3, 4})
+
+	result, ok := original.First(func(n int) bool { return n%2 == 0 })
+
+	fmt.Println(result)
+	fmt.Println(ok)
+
+	// Output:
+	// 2
+	// true
+}
+
+func ExampleStream_Last() {
+	original := FromSlice([]int{1, 2, 3, 4})
+
+	result, ok := original.Last(func(n int) bool { return n%2 == 1 })
+
+	fmt.Println(result)
+	fmt.Println(ok)
+
+	// Output:
+	// 3
+	// true
+}
+
 func ExampleStream_Reverse() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -314,6 +608,19 @@ func ExampleStream_Reverse() {
 	// [3 2 1]
 }
 
+func ExampleStream_ReverseInPlace() {
+	data := []int{1, 2, 3}
+
+	reversed := FromSlice(data).ReverseInPlace()
+
+	fmt.Println(reversed.ToSlice())
+	fmt.Println(data)
+
+	// Output:
+	// [3 2 1]
+	// [3 2 1]
+}
+
 func ExampleStream_Range() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -347,6 +654,67 @@ func ExampleStream_Sorted() {
 	// [1 2 3 4]
 }
 
+func ExampleStream_SortedReverse() {
+	original := FromSlice([]int{4, 2, 1, 3})
+
+	sorted := original.SortedReverse(func(a, b int) bool { return a < b })
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [4 3 2 1]
+}
+
+func ExampleStream_Shuffle() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	shuffled := original.Shuffle()
+
+	fmt.Println(shuffled.Count())
+	fmt.Println(original.ToSlice())
+
+	// Output:
+	// 5
+	// [1 2 3 4 5]
+}
+
+func ExampleStream_ShuffleWithSeed() {
+	original := FromRange(1, 10, 1)
+
+	s1 := original.ShuffleWithSeed(42).ToSlice()
+	s2 := original.ShuffleWithSeed(42).ToSlice()
+
+	fmt.Println(reflect.DeepEqual(s1, s2))
+
+	// Output:
+	// true
+}
+
+func ExampleStream_Sample() {
+	original := FromRange(1, 1000, 1)
+
+	sample := original.Sample(10)
+
+	fmt.Println(sample.Count())
+
+	// Output:
+	// 10
+}
+
+func ExampleStream_SampleWithSeed() {
+	original := FromRange(1, 1000, 1)
+
+	s1 := original.SampleWithSeed(5, 42).ToSlice()
+	s2 := original.SampleWithSeed(5, 42).ToSlice()
+
+	fmt.Println(len(s1))
+	fmt.Println(reflect.DeepEqual(s1, s2))
+
+	// Output:
+	// 5
+	// true
+}
+
 func ExampleStream_Max() {
 	original := FromSlice([]int{4, 2, 1, 3})
 
@@ -399,6 +767,20 @@ func ExampleStream_IndexOf() {
 	// 1
 }
 
+func ExampleStream_IndexOfBy() {
+	s := FromSlice([]int{1, 2, 3, 4})
+
+	result1 := s.IndexOfBy(func(n int) bool { return n > 4 })
+	result2 := s.IndexOfBy(func(n int) bool { return n%2 == 0 })
+
+	fmt.Println(result1)
+	fmt.Println(result2)
+
+	// Output:
+	// -1
+	// 1
+}
+
 func ExampleStream_LastIndexOf() {
 	s := FromSlice([]int{1, 2, 3, 2})
 
@@ -431,3 +813,521 @@ func ExampleToMap() {
 	// Output:
 	// map[Jim:{Jim 20} Mike:{Mike 30} Tom:{Tom 10}]
 }
+
+func ExampleStream_ToSliceN() {
+	original := Iterate(1, func(n int) int { return n * 2 })
+
+	fmt.Println(original.ToSliceN(5))
+
+	// Output:
+	// [1 2 4 8 16]
+}
+
+func ExampleStream_ToChannel() {
+	s := FromSlice([]int{1, 2, 3})
+
+	for v := range s.ToChannel(context.Background()) {
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleStream_Drain() {
+	out := make(chan int, 3)
+
+	FromSlice([]int{1, 2, 3}).Drain(out)
+	close(out)
+
+	for v := range out {
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleWindow() {
+	original := FromSlice([]int{1, 2, 3, 4})
+
+	windows := Window(original, 3, 1)
+
+	fmt.Println(windows.ToSlice())
+
+	// Output:
+	// [[1 2 3] [2 3 4]]
+}
+
+func ExampleZip() {
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]string{"a", "b", "c"})
+
+	zipped := Zip(s1, s2)
+
+	fmt.Println(zipped.ToSlice())
+
+	// Output:
+	// [{1 a} {2 b} {3 c}]
+}
+
+func ExampleWithIndex() {
+	s := FromSlice([]string{"a", "b", "c", "d"})
+
+	even := WithIndex(s).Filter(func(item Indexed[string]) bool {
+		return item.Index%2 == 0
+	})
+
+	fmt.Println(even.ToSlice())
+
+	// Output:
+	// [{0 a} {2 c}]
+}
+
+func ExampleUnzip() {
+	zipped := Zip(FromSlice([]int{1, 2, 3}), FromSlice([]string{"a", "b", "c"}))
+
+	numbers, letters := Unzip(zipped)
+
+	fmt.Println(numbers)
+	fmt.Println(letters)
+
+	// Output:
+	// [1 2 3]
+	// [a b c]
+}
+
+func ExampleZipWith() {
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]string{"a", "b", "c"})
+
+	zipped := ZipWith(s1, s2, func(n int, s string) string {
+		return fmt.Sprint(s, n)
+	})
+
+	fmt.Println(zipped.ToSlice())
+
+	// Output:
+	// [a1 b2 c3]
+}
+
+func ExampleSum() {
+	s := FromSlice([]int{1, 2, 3})
+
+	fmt.Println(Sum(s))
+
+	// Output:
+	// 6
+}
+
+func ExampleAverage() {
+	s := FromSlice([]int{1, 2, 3, 4})
+
+	fmt.Println(Average(s))
+
+	// Output:
+	// 2.5
+}
+
+func ExampleSummarize() {
+	s := FromSlice([]int{3, 1, 4, 2})
+
+	stats := Summarize(s)
+
+	fmt.Println(stats.Count, stats.Sum, stats.Min, stats.Max, stats.Average)
+
+	// Output:
+	// 4 10 1 4 2.5
+}
+
+func ExampleStream_WithContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := FromRange(1, 10, 1).WithContext(ctx)
+
+	s.ForEach(func(n int) {
+		fmt.Println(n)
+		if n == 3 {
+			cancel()
+		}
+	})
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleDistinctComparable() {
+	original := FromSlice([]int{1, 2, 2, 3, 3, 3})
+	distinct := DistinctComparable(original)
+
+	fmt.Println(distinct.ToSlice())
+
+	// Output:
+	// [1 2 3]
+}
+
+func ExampleDistinctBy() {
+	original := FromSlice([]string{"a", "bb", "cc", "d", "eee"})
+
+	distinct := DistinctBy(original, func(s string) int {
+		return len(s)
+	})
+
+	fmt.Println(distinct.ToSlice())
+
+	// Output:
+	// [a bb eee]
+}
+
+func ExampleFold() {
+	s := FromSlice([]string{"a", "bb", "ccc"})
+
+	length := Fold(s, 0, func(acc int, item string) int {
+		return acc + len(item)
+	})
+
+	fmt.Println(length)
+
+	// Output:
+	// 6
+}
+
+func ExampleCollectWith() {
+	type summary struct {
+		Count int
+		Total int
+	}
+
+	reducer := NewReducer(func() summary {
+		return summary{}
+	}, func(result summary, item int) summary {
+		result.Count++
+		result.Total += item
+		return result
+	})
+
+	result := CollectWith(FromSlice([]int{1, 2, 3, 4}), reducer)
+
+	fmt.Printf("%+v\n", result)
+
+	// Output:
+	// {Count:4 Total:10}
+}
+
+func ExampleScanTo() {
+	s := FromSlice([]string{"a", "bb", "ccc"})
+
+	result := ScanTo(s, 0, func(acc int, item string) int {
+		return acc + len(item)
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [0 1 3 6]
+}
+
+func ExampleAssociate() {
+	s := FromSlice([]string{"a", "bb", "ccc"})
+
+	m := Associate(s, func(item string) (string, int) {
+		return item, len(item)
+	})
+
+	fmt.Println(m)
+
+	// Output:
+	// map[a:1 bb:2 ccc:3]
+}
+
+func ExampleJoin() {
+	s := FromSlice([]string{"a", "b", "c"})
+
+	fmt.Println(Join(s, ","))
+
+	// Output:
+	// a,b,c
+}
+
+func ExampleJoinWith() {
+	s := FromSlice([]int{1, 2, 3})
+
+	result := JoinWith(s, "-", func(n int) string {
+		return fmt.Sprint(n)
+	})
+
+	fmt.Println(result)
+
+	// Output:
+	// 1-2-3
+}
+
+func ExampleFlatten() {
+	s := FromSlice([][]int{{1, 2}, {3}, {4, 5}})
+
+	fmt.Println(Flatten(s).ToSlice())
+
+	// Output:
+	// [1 2 3 4 5]
+}
+
+func ExampleContain() {
+	s := FromSlice([]int{1, 2, 3})
+
+	fmt.Println(Contain(s, 2))
+	fmt.Println(Contain(s, 4))
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleMaxBy() {
+	s := FromSlice([]string{"a", "ccc", "bb"})
+
+	longest, ok := MaxBy(s, func(s string) int { return len(s) })
+
+	fmt.Println(longest)
+	fmt.Println(ok)
+
+	// Output:
+	// ccc
+	// true
+}
+
+func ExampleMinBy() {
+	s := FromSlice([]string{"ccc", "a", "bb"})
+
+	shortest, ok := MinBy(s, func(s string) int { return len(s) })
+
+	fmt.Println(shortest)
+	fmt.Println(ok)
+
+	// Output:
+	// a
+	// true
+}
+
+func ExampleSortedBy() {
+	s := FromSlice([]string{"ccc", "a", "bb", "d"})
+
+	sorted := SortedBy(s, func(s string) int { return len(s) })
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [a d bb ccc]
+}
+
+func ExampleSortedByDesc() {
+	s := FromSlice([]string{"ccc", "a", "bb", "d"})
+
+	sorted := SortedByDesc(s, func(s string) int { return len(s) })
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [ccc bb a d]
+}
+
+func ExampleToSet() {
+	s := FromSlice([]int{1, 2, 2, 3, 3, 3})
+
+	set := ToSet(s)
+
+	_, ok := set[2]
+
+	fmt.Println(len(set))
+	fmt.Println(ok)
+
+	// Output:
+	// 3
+	// true
+}
+
+func ExampleFrequency() {
+	s := FromSlice([]string{"a", "b", "a", "c", "a", "b"})
+
+	frequency := Frequency(s)
+
+	fmt.Println(frequency["a"])
+	fmt.Println(frequency["b"])
+	fmt.Println(frequency["c"])
+
+	// Output:
+	// 3
+	// 2
+	// 1
+}
+
+func ExampleInterleave() {
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{4})
+	s3 := FromSlice([]int{5, 6})
+
+	s := Interleave(s1, s2, s3)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 4 5 2 6 3]
+}
+
+func ExampleTee() {
+	s := FromSlice([]int{1, 2, 3, 4}).Map(func(item int) int {
+		return item * item
+	})
+
+	streams := Tee(s, 2)
+
+	fmt.Println(streams[0].ToSlice())
+	fmt.Println(Sum(streams[1]))
+
+	// Output:
+	// [1 4 9 16]
+	// 30
+}
+
+func ExampleTopGroups() {
+	s := FromSlice([]string{"chrome", "firefox", "chrome", "safari", "chrome", "firefox"})
+
+	top := TopGroups(s, func(item string) string { return item }, 2)
+
+	fmt.Println(top)
+
+	// Output:
+	// [{chrome 3} {firefox 2}]
+}
+
+func ExampleStream_ForEachChunk() {
+	rows := FromRange(1, 5, 1)
+
+	err := rows.ForEachChunk(2, func(batch []int) error {
+		fmt.Println("insert", batch)
+		return nil
+	})
+
+	fmt.Println(err)
+
+	// Output:
+	// insert [1 2]
+	// insert [3 4]
+	// insert [5]
+	// <nil>
+}
+
+func ExampleProcessBatches() {
+	s := FromRange(1, 5, 1)
+
+	err := ProcessBatches(s, 2, func(batch []int) error {
+		fmt.Println(batch)
+		return nil
+	})
+
+	fmt.Println(err)
+
+	// Output:
+	// [1 2]
+	// [3 4]
+	// [5]
+	// <nil>
+}
+
+func ExampleMapE() {
+	s := FromSlice([]string{"1", "2", "3"})
+
+	result, err := MapE(s, strconv.Atoi)
+	fmt.Println(result, err)
+
+	_, err = MapE(FromSlice([]string{"1", "a"}), strconv.Atoi)
+	fmt.Println(err)
+
+	// Output:
+	// [1 2 3] <nil>
+	// strconv.Atoi: parsing "a": invalid syntax
+}
+
+func ExampleMapParallel() {
+	s := FromSlice([]int{1, 2, 3, 4, 5})
+
+	result := MapParallel(s, 2, func(n int) string {
+		return strconv.Itoa(n * n)
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [1 4 9 16 25]
+}
+
+func ExampleCompact() {
+	s := FromSlice([]string{"a", "", "b", "", "c"})
+
+	fmt.Println(Compact(s).ToSlice())
+
+	// Output:
+	// [a b c]
+}
+
+func ExampleDedup() {
+	s := FromSlice([]int{1, 1, 2, 2, 2, 1, 3})
+
+	fmt.Println(Dedup(s).ToSlice())
+
+	// Output:
+	// [1 2 1 3]
+}
+
+func ExampleDedupBy() {
+	s := FromSlice([]string{"apple", "avocado", "banana", "apricot"})
+
+	result := DedupBy(s, func(item string) byte { return item[0] })
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [apple banana apricot]
+}
+
+func ExampleGroupAdjacent() {
+	logs := FromSlice([]string{"10:01 start", "10:01 load", "10:02 run", "10:03 stop", "10:03 exit"})
+
+	groups := GroupAdjacent(logs, func(line string) string {
+		return line[:5]
+	})
+
+	for _, group := range groups.ToSlice() {
+		fmt.Println(group)
+	}
+
+	// Output:
+	// [10:01 start 10:01 load]
+	// [10:02 run]
+	// [10:03 stop 10:03 exit]
+}
+
+func ExampleEqual() {
+	fmt.Println(Equal(FromSlice([]int{1, 2, 3}), FromRange(1, 3, 1)))
+	fmt.Println(Equal(FromSlice([]int{1, 2, 3}), FromSlice([]int{1, 2})))
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleEqualBy() {
+	a := FromSlice([]string{"Go", "Lancet"})
+	b := FromSlice([]string{"go", "LANCET"})
+
+	fmt.Println(EqualBy(a, b, strings.EqualFold))
+
+	// Output:
+	// true
+}
@@ -1,8 +1,15 @@
 package stream
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/duke-git/lancet/v2/internal"
 )
@@ -36,6 +43,71 @@ func TestGenerate(t *testing.T) {
 	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
 }
 
+func TestGenerateN(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGenerateN")
+
+	calls := 0
+	stream := GenerateN(4, func(index int) int {
+		calls++
+		return index * index
+	})
+
+	assert.Equal([]int{0, 1, 4, 9}, stream.ToSlice())
+	assert.Equal(4, calls)
+
+	assert.Equal([]int{}, GenerateN(0, func(index int) int { return index }).ToSlice())
+	assert.Equal([]int{}, GenerateN(-1, func(index int) int { return index }).ToSlice())
+}
+
+func TestIterate(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestIterate")
+
+	calls := 0
+	stream := Iterate(1, func(n int) int {
+		calls++
+		return n * 2
+	})
+
+	assert.Equal([]int{1, 2, 4, 8, 16}, stream.Limit(5).ToSlice())
+	assert.Equal(4, calls)
+
+	assert.Equal([]int{1, 2, 4}, stream.Limit(3).ToSlice())
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEmpty")
+
+	assert.Equal([]int{}, Empty[int]().ToSlice())
+	assert.Equal(0, Empty[string]().Count())
+}
+
+func TestRepeat(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRepeat")
+
+	assert.Equal([]string{"a", "a", "a"}, Repeat("a", 3).ToSlice())
+	assert.Equal([]int{}, Repeat(1, 0).ToSlice())
+	assert.Equal([]int{}, Repeat(1, -1).ToSlice())
+}
+
+func TestRepeatInfinite(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRepeatInfinite")
+
+	assert.Equal([]int{0, 0, 0, 0}, RepeatInfinite(0).Limit(4).ToSlice())
+
+	padded := Concat(FromSlice([]int{1, 2}), RepeatInfinite(0)).Limit(5)
+	assert.Equal([]int{1, 2, 0, 0, 0}, padded.ToSlice())
+}
+
 func TestFromSlice(t *testing.T) {
 	t.Parallel()
 
@@ -75,6 +147,34 @@ func TestFromRange(t *testing.T) {
 	assert.Equal([]float64{1.1, 2.1, 3.1, 4.1}, s2.ToSlice())
 }
 
+func TestStream_Cache(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Cache")
+
+	calls := 0
+	stream := FromSlice([]int{1, 2, 3}).Map(func(n int) int {
+		calls++
+		return n * 2
+	}).Cache()
+
+	assert.Equal(0, calls)
+	assert.Equal(3, stream.Count())
+	assert.Equal(12, Sum(stream))
+	assert.Equal([]int{2, 4, 6}, stream.ToSlice())
+	assert.Equal(3, calls)
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+
+	cached := FromChannel(ch).Cache()
+	assert.Equal([]int{1, 2, 3}, cached.ToSlice())
+	assert.Equal([]int{1, 2, 3}, cached.ToSlice())
+}
+
 func TestStream_Distinct(t *testing.T) {
 	t.Parallel()
 
@@ -109,6 +209,33 @@ func TestStream_Distinct(t *testing.T) {
 	t.Log(distinctStream)
 }
 
+func TestStream_DistinctByHash(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_DistinctByHash")
+
+	// gob can't encode the func field.
+	type Task struct {
+		Name string
+		Run  func()
+	}
+
+	tasks := FromSlice([]Task{
+		{Name: "build"},
+		{Name: "test"},
+		{Name: "build"},
+	})
+
+	distinct := tasks.DistinctByHash(func(item Task) string { return item.Name })
+
+	names := make([]string, 0)
+	distinct.ForEach(func(item Task) {
+		names = append(names, item.Name)
+	})
+	assert.Equal([]string{"build", "test"}, names)
+	assert.Equal(3, tasks.Count())
+}
+
 func TestStream_Filter(t *testing.T) {
 	t.Parallel()
 
@@ -155,6 +282,63 @@ func TestStream_Peek(t *testing.T) {
 	assert.Equal([]string{
 		"current: 1", "current: 2", "current: 3",
 	}, result)
+
+	// the consumer is only called for the elements actually consumed.
+	peeked := []int{}
+	limited := FromSlice([]int{1, 2, 3, 4, 5, 6}).Filter(func(n int) bool {
+		return n%2 == 0
+	}).Peek(func(n int) {
+		peeked = append(peeked, n)
+	}).Limit(2)
+
+	assert.Equal([]int{}, peeked)
+	assert.Equal([]int{2, 4}, limited.ToSlice())
+	assert.Equal([]int{2, 4}, peeked)
+}
+
+func TestStream_PeekLimit(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_PeekLimit")
+
+	peeked := []int{}
+	stream := FromRange(1, 100, 1).PeekLimit(3, func(n int) {
+		peeked = append(peeked, n)
+	})
+
+	assert.Equal(100, stream.Count())
+	assert.Equal([]int{1, 2, 3}, peeked)
+
+	peeked = []int{}
+	assert.Equal([]int{1, 2}, stream.Limit(2).ToSlice())
+	assert.Equal([]int{1, 2}, peeked)
+
+	peeked = []int{}
+	assert.Equal(100, FromRange(1, 100, 1).PeekLimit(0, func(n int) {
+		peeked = append(peeked, n)
+	}).Count())
+	assert.Equal([]int{}, peeked)
+}
+
+func TestStream_PeekIndexed(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_PeekIndexed")
+
+	result := []string{}
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6}).PeekIndexed(func(index, n int) {
+		result = append(result, fmt.Sprintf("%d:%d", index, n))
+	}).Filter(func(n int) bool {
+		return n%2 == 0
+	})
+
+	assert.Equal([]int{2, 4}, stream.Limit(2).ToSlice())
+	assert.Equal([]string{"0:1", "1:2", "2:3", "3:4"}, result)
+
+	// the index restarts for each terminal operation.
+	result = result[:0]
+	assert.Equal(3, stream.Count())
+	assert.Equal([]string{"0:1", "1:2", "2:3", "3:4", "4:5", "5:6"}, result)
 }
 
 func TestStream_Skip(t *testing.T) {
@@ -173,6 +357,54 @@ func TestStream_Skip(t *testing.T) {
 	assert.Equal([]int{3, 4}, s4.ToSlice())
 }
 
+func TestStream_SkipHugeArgument(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_SkipHugeArgument")
+
+	stream := FromSlice([]int{1, 2, 3})
+	assert.Equal([]int{}, stream.Skip(1<<60).ToSlice())
+	assert.Equal([]int{}, stream.Skip(math.MaxInt).ToSlice())
+	assert.Equal([]int{}, stream.SkipLast(math.MaxInt).ToSlice())
+
+	lazy := FromRange(1, 3, 1).Map(func(n int) int { return n * 10 })
+	assert.Equal([]int{}, lazy.Skip(1<<60).ToSlice())
+	assert.Equal([]int{}, lazy.Skip(math.MaxInt).ToSlice())
+	assert.Equal([]int{}, lazy.SkipLast(math.MaxInt).ToSlice())
+}
+
+func TestStream_SkipLast(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_SkipLast")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, stream.SkipLast(0).ToSlice())
+	assert.Equal([]int{1, 2, 3, 4}, stream.SkipLast(1).ToSlice())
+	assert.Equal([]int{1, 2}, stream.SkipLast(3).ToSlice())
+	assert.Equal([]int{}, stream.SkipLast(5).ToSlice())
+	assert.Equal([]int{}, stream.SkipLast(10).ToSlice())
+
+	lazy := FromRange(1, 5, 1).Map(func(n int) int { return n * 10 })
+	assert.Equal([]int{10, 20, 30, 40, 50}, lazy.SkipLast(0).ToSlice())
+	assert.Equal([]int{10, 20, 30, 40}, lazy.SkipLast(1).ToSlice())
+	assert.Equal([]int{10, 20}, lazy.SkipLast(3).ToSlice())
+	assert.Equal([]int{}, lazy.SkipLast(5).ToSlice())
+	assert.Equal([]int{}, lazy.SkipLast(10).ToSlice())
+
+	// the lazy stream pulls only n elements ahead.
+	pulled := 0
+	counted := FromRange(1, 100, 1).Peek(func(int) { pulled++ })
+	assert.Equal([]int{1, 2}, counted.SkipLast(3).Limit(2).ToSlice())
+	assert.Equal(5, pulled)
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	stream.SkipLast(-1)
+}
+
 func TestStream_Limit(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_Limit")
 
@@ -189,6 +421,58 @@ func TestStream_Limit(t *testing.T) {
 	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s4.ToSlice())
 }
 
+func TestStream_LimitHugeArgument(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_LimitHugeArgument")
+
+	stream := FromSlice([]int{1, 2, 3})
+	assert.Equal([]int{1, 2, 3}, stream.Limit(1<<60).ToSlice())
+	assert.Equal([]int{1, 2, 3}, stream.Limit(math.MaxInt).ToSlice())
+	assert.Equal([]int{}, stream.Limit(math.MinInt).ToSlice())
+
+	lazy := FromRange(1, 3, 1).Map(func(n int) int { return n * 10 })
+	assert.Equal([]int{10, 20, 30}, lazy.Limit(1<<60).ToSlice())
+	assert.Equal([]int{10, 20, 30}, lazy.Limit(math.MaxInt).ToSlice())
+	assert.Equal([]int{}, lazy.Limit(math.MinInt).ToSlice())
+
+	// an infinite stream is still bounded by the inner limit.
+	infinite := Iterate(1, func(n int) int { return n + 1 })
+	assert.Equal([]int{1, 2}, infinite.Limit(math.MaxInt).Limit(2).ToSlice())
+	assert.Equal([]int{1, 2}, infinite.Limit(2).Limit(math.MaxInt).ToSlice())
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestChunk")
+
+	s := FromSlice([]int{1, 2, 3, 4, 5})
+
+	assert.Equal([][]int{{1, 2}, {3, 4}, {5}}, Chunk(s, 2).ToSlice())
+	assert.Equal([][]int{{1, 2, 3, 4, 5}}, Chunk(s, 5).ToSlice())
+	assert.Equal([][]int{{1, 2, 3, 4, 5}}, Chunk(s, 10).ToSlice())
+	assert.Equal([][]int{}, Chunk(FromSlice([]int{}), 2).ToSlice())
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	Chunk(s, 0)
+}
+
+func TestChunkHugeSize(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestChunkHugeSize")
+
+	stream := FromSlice([]int{1, 2, 3})
+	assert.Equal([][]int{{1, 2, 3}}, Chunk(stream, 1<<40).ToSlice())
+	assert.Equal([][]int{{1, 2, 3}}, Chunk(stream, math.MaxInt).ToSlice())
+
+	lazy := FromRange(1, 3, 1).Map(func(n int) int { return n * 10 })
+	assert.Equal([][]int{{10, 20, 30}}, Chunk(lazy, math.MaxInt).ToSlice())
+}
+
 func TestStream_AllMatch(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_AllMatch")
 
@@ -253,6 +537,51 @@ func TestStream_ForEach(t *testing.T) {
 	assert.Equal(6, result)
 }
 
+func TestStream_ForEachIndexed(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachIndexed")
+
+	stream := FromSlice([]string{"a", "b", "c", "d"})
+
+	result := []string{}
+	stream.Limit(3).ForEachIndexed(func(index int, item string) {
+		result = append(result, fmt.Sprint(index, item))
+	})
+
+	assert.Equal([]string{"0a", "1b", "2c"}, result)
+}
+
+func TestStream_ForEachWhile(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachWhile")
+
+	pulled := 0
+	stream := Iterate(1, func(n int) int { return n + 1 }).Peek(func(n int) {
+		pulled++
+	})
+
+	result := []int{}
+	stream.ForEachWhile(func(item int) bool {
+		if item > 3 {
+			return false
+		}
+		result = append(result, item)
+		return true
+	})
+
+	assert.Equal([]int{1, 2, 3}, result)
+	assert.Equal(4, pulled)
+
+	result = []int{}
+	FromSlice([]int{1, 2}).ForEachWhile(func(item int) bool {
+		result = append(result, item)
+		return true
+	})
+	assert.Equal([]int{1, 2}, result)
+}
+
 func TestStream_Reduce(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_Reduce")
 
@@ -265,6 +594,69 @@ func TestStream_Reduce(t *testing.T) {
 	assert.Equal(6, result)
 }
 
+func TestStream_ReduceOptional(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ReduceOptional")
+
+	sum := func(a, b int) int { return a + b }
+
+	result, ok := FromSlice([]int{1, 2, 3}).ReduceOptional(sum)
+	assert.Equal(6, result)
+	assert.Equal(true, ok)
+
+	result, ok = FromSlice([]int{5}).ReduceOptional(sum)
+	assert.Equal(5, result)
+	assert.Equal(true, ok)
+
+	result, ok = FromSlice([]int{}).ReduceOptional(sum)
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+}
+
+func TestStream_RunningMin(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_RunningMin")
+
+	less := func(a, b int) bool { return a < b }
+
+	assert.Equal([]int{3, 3, 1, 1, 0}, FromSlice([]int{3, 5, 1, 2, 0}).RunningMin(less).ToSlice())
+	assert.Equal([]int{3, 5, 5, 7}, FromSlice([]int{3, 5, 1, 7}).RunningMax(less).ToSlice())
+
+	assert.Equal([]int{4}, FromSlice([]int{4}).RunningMin(less).ToSlice())
+	assert.Equal([]int{4}, FromSlice([]int{4}).RunningMax(less).ToSlice())
+	assert.Equal([]int{}, FromSlice([]int{}).RunningMin(less).ToSlice())
+	assert.Equal([]int{}, FromSlice([]int{}).RunningMax(less).ToSlice())
+
+	// of the equal elements, the earlier one is kept.
+	type item struct {
+		value int
+		name  string
+	}
+	byValue := func(a, b item) bool { return a.value < b.value }
+	items := FromSlice([]item{{2, "a"}, {2, "b"}, {1, "c"}})
+
+	assert.Equal([]item{{2, "a"}, {2, "a"}, {1, "c"}}, items.RunningMin(byValue).ToSlice())
+	assert.Equal([]item{{2, "a"}, {2, "a"}, {2, "a"}}, items.RunningMax(byValue).ToSlice())
+}
+
+func TestStream_Scan(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Scan")
+
+	stream := FromSlice([]int{1, 2, 3, 4})
+
+	sums := stream.Scan(0, func(a, b int) int {
+		return a + b
+	})
+
+	assert.Equal([]int{0, 1, 3, 6, 10}, sums.ToSlice())
+	assert.Equal([]int{0, 1, 3}, sums.Limit(3).ToSlice())
+	assert.Equal([]int{1}, FromSlice([]int{}).Scan(1, func(a, b int) int { return a * b }).ToSlice())
+}
+
 func TestStream_Count(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_Count")
 
@@ -273,6 +665,11 @@ func TestStream_Count(t *testing.T) {
 
 	assert.Equal(3, s1.Count())
 	assert.Equal(0, s2.Count())
+
+	s3 := Iterate(1, func(item int) int { return item + 1 }).Limit(100).Filter(func(item int) bool {
+		return item%3 == 0
+	})
+	assert.Equal(33, s3.Count())
 }
 
 func TestStream_FindFirst(t *testing.T) {
@@ -284,6 +681,26 @@ func TestStream_FindFirst(t *testing.T) {
 
 	assert.Equal(1, result)
 	assert.Equal(true, ok)
+
+	result, ok = FromSlice[int](nil).FindFirst()
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	// lazy streams
+	result, ok = FromSlice([]int{1, 2, 3}).Filter(func(n int) bool { return n > 5 }).FindFirst()
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	result, ok = FromSlice([]int{1, 2, 3}).Filter(func(n int) bool { return n == 2 }).FindFirst()
+	assert.Equal(2, result)
+	assert.Equal(true, ok)
+
+	// short-circuits on an infinite stream
+	pulled := 0
+	result, ok = Iterate(1, func(n int) int { return n + 1 }).Peek(func(int) { pulled++ }).FindFirst()
+	assert.Equal(1, result)
+	assert.Equal(true, ok)
+	assert.Equal(1, pulled)
 }
 
 func TestStream_FindLast(t *testing.T) {
@@ -302,6 +719,106 @@ func TestStream_FindLast(t *testing.T) {
 
 	assert.Equal(0, result)
 	assert.Equal(false, ok)
+
+	result, ok = FromSlice[int](nil).FindLast()
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	// lazy streams
+	result, ok = FromSlice([]int{1, 2, 3}).Filter(func(n int) bool { return n > 5 }).FindLast()
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	result, ok = FromSlice([]int{1, 2, 3}).Filter(func(n int) bool { return n == 2 }).FindLast()
+	assert.Equal(2, result)
+	assert.Equal(true, ok)
+
+	result, ok = Iterate(1, func(n int) int { return n + 1 }).Limit(5).FindLast()
+	assert.Equal(5, result)
+	assert.Equal(true, ok)
+}
+
+func TestStream_Nth(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Nth")
+
+	s := FromSlice([]int{10, 20, 30, 40})
+
+	cases := []struct {
+		index    int
+		expected int
+		ok       bool
+	}{
+		{0, 10, true},
+		{2, 30, true},
+		{3, 40, true},
+		{4, 0, false},
+		{-1, 40, true},
+		{-3, 20, true},
+		{-4, 10, true},
+		{-5, 0, false},
+		{-1 << 50, 0, false},
+		{math.MinInt, 0, false},
+		{math.MaxInt, 0, false},
+	}
+
+	for _, c := range cases {
+		item, ok := s.Nth(c.index)
+		assert.Equal(c.expected, item)
+		assert.Equal(c.ok, ok)
+	}
+
+	pulled := 0
+	item, ok := Iterate(0, func(n int) int { return n + 1 }).Peek(func(n int) {
+		pulled++
+	}).Nth(5)
+	assert.Equal(5, item)
+	assert.Equal(true, ok)
+	assert.Equal(6, pulled)
+
+	_, ok = FromSlice([]int{}).Nth(-1)
+	assert.Equal(false, ok)
+}
+
+func TestStream_First(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_First")
+
+	visited := 0
+	stream := FromSlice([]int{1, 2, 3, 4}).Peek(func(n int) {
+		visited++
+	})
+
+	result, ok := stream.First(func(n int) bool { return n%2 == 0 })
+
+	assert.Equal(2, result)
+	assert.Equal(true, ok)
+	assert.Equal(2, visited)
+
+	result, ok = stream.First(func(n int) bool { return n > 4 })
+
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+}
+
+func TestStream_Last(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Last")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	result, ok := stream.Last(func(n int) bool { return n%2 == 0 })
+
+	assert.Equal(4, result)
+	assert.Equal(true, ok)
+
+	result, ok = stream.Last(func(n int) bool { return n > 5 })
+
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
 }
 
 func TestStream_Reverse(t *testing.T) {
@@ -314,6 +831,34 @@ func TestStream_Reverse(t *testing.T) {
 	assert.Equal([]int{3, 2, 1}, rs.ToSlice())
 }
 
+func TestStream_ReverseInPlace(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ReverseInPlace")
+
+	source := []int{1, 2, 3, 4}
+	reversed := FromSlice(source).ReverseInPlace()
+
+	assert.Equal([]int{4, 3, 2, 1}, reversed.ToSlice())
+	// the source slice is mutated.
+	assert.Equal([]int{4, 3, 2, 1}, source)
+
+	odd := []int{1, 2, 3}
+	FromSlice(odd).ReverseInPlace()
+	assert.Equal([]int{3, 2, 1}, odd)
+
+	assert.Equal([]int{}, FromSlice([]int{}).ReverseInPlace().ToSlice())
+
+	// a lazy stream is reversed like Reverse.
+	lazy := FromRange(1, 3, 1).Map(func(n int) int { return n * 10 })
+	assert.Equal([]int{30, 20, 10}, lazy.ReverseInPlace().ToSlice())
+
+	// Reverse doesn't mutate the source.
+	kept := []int{1, 2, 3}
+	assert.Equal([]int{3, 2, 1}, FromSlice(kept).Reverse().ToSlice())
+	assert.Equal([]int{1, 2, 3}, kept)
+}
+
 func TestStream_Range(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_Range")
 
@@ -347,51 +892,254 @@ func TestStream_Concat(t *testing.T) {
 	s := Concat(s1, s2)
 
 	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s.ToSlice())
-}
-
-func TestStream_Sorted(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Sorted")
-
-	s := FromSlice([]int{4, 2, 1, 3})
 
-	s1 := s.Sorted(func(a, b int) bool { return a < b })
+	s3 := FromSlice([]int{})
+	s4 := FromSlice([]int{7})
 
-	assert.Equal([]int{4, 2, 1, 3}, s.ToSlice())
-	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7}, Concat(s1, s3, s2, s4).ToSlice())
+	assert.Equal([]int{1, 2, 3}, Concat(s1).ToSlice())
+	assert.Equal([]int{}, Concat[int]().ToSlice())
 }
 
-func TestStream_Max(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Max")
+func TestStream_Append(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3})
+	assert := internal.NewAssert(t, "TestStream_Append")
 
-	max, ok := s.Max(func(a, b int) bool { return a > b })
+	s := FromSlice([]int{1, 2})
 
-	assert.Equal(4, max)
-	assert.Equal(true, ok)
+	assert.Equal([]int{1, 2, 3, 4}, s.Append(3, 4).ToSlice())
+	assert.Equal([]int{1, 2}, s.Append().ToSlice())
+	assert.Equal([]int{1, 2}, s.ToSlice())
+	assert.Equal([]int{1, 2, 3}, Iterate(1, func(n int) int { return n + 1 }).Limit(2).Append(3).ToSlice())
 }
 
-func TestStream_Min(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Min")
+func TestStream_Prepend(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3})
+	assert := internal.NewAssert(t, "TestStream_Prepend")
 
-	max, ok := s.Max(func(a, b int) bool { return a < b })
+	s := FromSlice([]int{3, 4})
 
-	assert.Equal(1, max)
-	assert.Equal(true, ok)
+	assert.Equal([]int{1, 2, 3, 4}, s.Prepend(1, 2).ToSlice())
+	assert.Equal([]int{3, 4}, s.Prepend().ToSlice())
+	assert.Equal([]int{0, 1, 2}, Iterate(1, func(n int) int { return n + 1 }).Prepend(0).Limit(3).ToSlice())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal([]int{}, s.WithContext(ctx).Prepend(1, 2).ToSlice())
 }
 
-func TestStream_IndexOf(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_IndexOf")
+func TestStream_Intersperse(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3, 4})
+	assert := internal.NewAssert(t, "TestStream_Intersperse")
+
+	assert.Equal([]int{1, 0, 2, 0, 3}, FromSlice([]int{1, 2, 3}).Intersperse(0).ToSlice())
+	assert.Equal([]int{1}, FromSlice([]int{1}).Intersperse(0).ToSlice())
+	assert.Equal([]int{}, FromSlice([]int{}).Intersperse(0).ToSlice())
+	assert.Equal([]int{1, 0, 2}, Iterate(1, func(n int) int { return n + 1 }).Intersperse(0).Limit(3).ToSlice())
+	assert.Equal("a, b, c", Join(FromSlice([]string{"a", "b", "c"}).Intersperse(", "), ""))
+}
+
+func TestStream_Sorted(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Sorted")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	s1 := s.Sorted(func(a, b int) bool { return a < b })
+
+	assert.Equal([]int{4, 2, 1, 3}, s.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
+
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	people := FromSlice([]Person{
+		{Name: "Tom", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Bob", Age: 10},
+		{Name: "Lily", Age: 20},
+	})
+
+	sorted := people.Sorted(func(a, b Person) bool { return a.Age < b.Age })
+
+	assert.Equal([]Person{
+		{Name: "Jim", Age: 10},
+		{Name: "Bob", Age: 10},
+		{Name: "Tom", Age: 20},
+		{Name: "Mike", Age: 20},
+		{Name: "Lily", Age: 20},
+	}, sorted.ToSlice())
+}
+
+func TestStream_Shuffle(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Shuffle")
+
+	source := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	s := FromSlice(source)
+
+	shuffled := s.Shuffle().ToSlice()
+	assert.Equal(len(source), len(shuffled))
+	assert.Equal(source, FromSlice(shuffled).Sorted(func(a, b int) bool { return a < b }).ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, source)
+
+	assert.Equal([]int{}, FromSlice([]int{}).Shuffle().ToSlice())
+	assert.Equal([]int{1}, FromSlice([]int{1}).Shuffle().ToSlice())
+}
+
+func TestStream_ShuffleWithSeed(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_ShuffleWithSeed")
+
+	s := FromRange(1, 100, 1)
+
+	shuffled := s.ShuffleWithSeed(42)
+	assert.Equal(shuffled.ToSlice(), shuffled.ToSlice())
+	assert.Equal(shuffled.ToSlice(), s.ShuffleWithSeed(42).ToSlice())
+	assert.NotEqual(s.ToSlice(), shuffled.ToSlice())
+}
+
+func TestStream_Sample(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Sample")
+
+	s := FromRange(1, 100, 1)
+
+	sample := s.Sample(10).ToSlice()
+	assert.Equal(10, len(sample))
+	assert.Equal(10, DistinctComparable(FromSlice(sample)).Count())
+	for _, v := range sample {
+		assert.Equal(true, v >= 1 && v <= 100)
+	}
+
+	assert.Equal([]int{1, 2, 3}, FromSlice([]int{1, 2, 3}).Sample(5).ToSlice())
+	assert.Equal([]int{}, s.Sample(0).ToSlice())
+	assert.Equal([]int{}, s.Sample(-1).ToSlice())
+
+	lazy := Iterate(1, func(n int) int { return n + 1 }).Limit(1000).Sample(3)
+	assert.Equal(3, lazy.Count())
+}
+
+func TestStream_SampleWithSeed(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_SampleWithSeed")
+
+	s := FromRange(1, 1000, 1)
+
+	sample := s.SampleWithSeed(10, 42)
+	assert.Equal(10, len(sample.ToSlice()))
+	assert.Equal(sample.ToSlice(), sample.ToSlice())
+	assert.Equal(sample.ToSlice(), s.SampleWithSeed(10, 42).ToSlice())
+}
+
+func TestStream_SortedReverse(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_SortedReverse")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	assert.Equal([]int{4, 3, 2, 1}, s.SortedReverse(func(a, b int) bool { return a < b }).ToSlice())
+
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	people := FromSlice([]Person{
+		{Name: "Tom", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Bob", Age: 10},
+	})
+
+	sorted := people.SortedReverse(func(a, b Person) bool { return a.Age < b.Age })
+
+	assert.Equal([]Person{
+		{Name: "Tom", Age: 20},
+		{Name: "Mike", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Bob", Age: 10},
+	}, sorted.ToSlice())
+}
+
+func TestStream_Max(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Max")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	max, ok := s.Max(func(a, b int) bool { return a > b })
+
+	assert.Equal(4, max)
+	assert.Equal(true, ok)
+
+	negative := FromSlice([]int{-4, -2, -1, -3})
+
+	max, ok = negative.Max(func(a, b int) bool { return a > b })
+
+	assert.Equal(-1, max)
+	assert.Equal(true, ok)
+
+	max, ok = FromSlice([]int{}).Max(func(a, b int) bool { return a > b })
+
+	assert.Equal(0, max)
+	assert.Equal(false, ok)
+}
+
+func TestStream_Min(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Min")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	min, ok := s.Min(func(a, b int) bool { return a < b })
+
+	assert.Equal(1, min)
+	assert.Equal(true, ok)
+
+	positive := FromSlice([]int{4, 2, 3})
+
+	min, ok = positive.Min(func(a, b int) bool { return a < b })
+
+	assert.Equal(2, min)
+	assert.Equal(true, ok)
+
+	negative := FromSlice([]int{-4, -2, -5, -3})
+
+	min, ok = negative.Min(func(a, b int) bool { return a < b })
+
+	assert.Equal(-5, min)
+	assert.Equal(true, ok)
+
+	min, ok = FromSlice([]int{}).Min(func(a, b int) bool { return a < b })
+
+	assert.Equal(0, min)
+	assert.Equal(false, ok)
+}
+
+func TestStream_IndexOf(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_IndexOf")
+
+	s := FromSlice([]int{4, 2, 1, 3, 4})
 
 	assert.Equal(-1, s.IndexOf(0, func(a, b int) bool { return a == b }))
 	assert.Equal(0, s.IndexOf(4, func(a, b int) bool { return a == b }))
 	assert.Equal(3, s.IndexOf(3, func(a, b int) bool { return a == b }))
 }
 
+func TestStream_IndexOfBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_IndexOfBy")
+
+	s := FromSlice([]int{4, 2, 1, 3, 4})
+
+	assert.Equal(-1, s.IndexOfBy(func(n int) bool { return n > 4 }))
+	assert.Equal(0, s.IndexOfBy(func(n int) bool { return n%2 == 0 }))
+	assert.Equal(2, s.IndexOfBy(func(n int) bool { return n%2 == 1 }))
+
+	// stops at the first matched element of an infinite stream.
+	assert.Equal(5, Iterate(0, func(n int) int { return n + 1 }).IndexOfBy(func(n int) bool { return n == 5 }))
+}
+
 func TestStream_LastIndexOf(t *testing.T) {
 	assert := internal.NewAssert(t, "TestStream_LastIndexOf")
 
@@ -423,3 +1171,1019 @@ func TestStream_ToMap(t *testing.T) {
 	assert.EqualValues(expected, m)
 
 }
+
+func TestStream_ToSlice(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ToSlice")
+
+	source := []int{1, 2, 3}
+	s := FromSlice(source)
+
+	result := s.ToSlice()
+	result[0] = 100
+	assert.Equal([]int{1, 2, 3}, s.ToSlice())
+	assert.Equal([]int{1, 2, 3}, source)
+
+	cached := FromSlice([]int{3, 1, 2}).Sorted(func(a, b int) bool { return a < b }).Cache()
+	result = cached.ToSlice()
+	result[0] = 100
+	assert.Equal([]int{1, 2, 3}, cached.ToSlice())
+}
+
+func TestStream_ToSliceN(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ToSliceN")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	assert.Equal([]int{1, 2}, s.ToSliceN(2))
+	assert.Equal([]int{1, 2, 3}, s.ToSliceN(5))
+	assert.Equal([]int{}, s.ToSliceN(0))
+	assert.Equal([]int{}, s.ToSliceN(-1))
+
+	pulled := 0
+	infinite := Iterate(1, func(n int) int { return n + 1 }).Peek(func(int) { pulled++ })
+	assert.Equal([]int{1, 2, 3}, infinite.ToSliceN(3))
+	assert.Equal(3, pulled)
+}
+
+func TestStream_ToChannel(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ToChannel")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	result := []int{}
+	for v := range s.ToChannel(context.Background()) {
+		result = append(result, v)
+	}
+
+	assert.Equal([]int{1, 2, 3}, result)
+
+	// a nil ctx is treated as context.Background().
+	var nilCtx context.Context
+	assert.Equal([]int{1, 2, 3}, FromChannel(s.ToChannel(nilCtx)).ToSlice())
+	assert.Equal([]int{1, 2, 3}, FromChannel(s.ToChannelWithBuffer(nilCtx, 1)).ToSlice())
+}
+
+func TestStream_ToChannelWithBuffer(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ToChannelWithBuffer")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	ch := s.ToChannelWithBuffer(context.Background(), 3)
+	assert.Equal(3, cap(ch))
+
+	result := FromChannel(ch).ToSlice()
+	assert.Equal([]int{1, 2, 3}, result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch = FromRange(1, 100, 1).ToChannel(ctx)
+
+	first := <-ch
+	cancel()
+
+	// drain the channel, it should be closed after ctx is canceled.
+	for range ch {
+	}
+
+	assert.Equal(1, first)
+}
+
+func TestStream_Drain(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Drain")
+
+	out := make(chan int, 1)
+	go func() {
+		FromSlice([]int{1, 2, 3}).Drain(out)
+		FromSlice([]int{4, 5}).Drain(out)
+		close(out)
+	}()
+
+	result := []int{}
+	for v := range out {
+		result = append(result, v)
+	}
+	assert.Equal([]int{1, 2, 3, 4, 5}, result)
+
+	// the blocked send is released once the ctx is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	blocked := make(chan int)
+	finished := make(chan struct{})
+	go func() {
+		FromRange(1, 100, 1).WithContext(ctx).Drain(blocked)
+		close(finished)
+	}()
+
+	assert.Equal(1, <-blocked)
+	cancel()
+	<-finished
+}
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestWindow")
+
+	s := FromSlice([]int{1, 2, 3, 4, 5})
+
+	assert.Equal([][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, Window(s, 3, 1).ToSlice())
+	assert.Equal([][]int{{1, 2}, {3, 4}}, Window(s, 2, 2).ToSlice())
+	assert.Equal([][]int{{1, 2}, {4, 5}}, Window(s, 2, 3).ToSlice())
+	assert.Equal([][]int{}, Window(s, 6, 1).ToSlice())
+	assert.Equal([][]int{}, Window(s, math.MaxInt, 1).ToSlice())
+	assert.Equal([][]int{{1, 2, 3, 4, 5}}, Window(s, 5, math.MaxInt).ToSlice())
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	Window(s, 2, 0)
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestZip")
+
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]string{"a", "b"})
+
+	expected := []Pair[int, string]{
+		{First: 1, Second: "a"},
+		{First: 2, Second: "b"},
+	}
+
+	assert.Equal(expected, Zip(s1, s2).ToSlice())
+	assert.Equal([]Pair[int, string]{}, Zip(s1, FromSlice([]string{})).ToSlice())
+}
+
+func TestWithIndex(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestWithIndex")
+
+	s := WithIndex(FromSlice([]string{"a", "b", "c"}))
+	assert.Equal([]Indexed[string]{{0, "a"}, {1, "b"}, {2, "c"}}, s.ToSlice())
+
+	even := s.Filter(func(item Indexed[string]) bool {
+		return item.Index%2 == 0
+	})
+	assert.Equal([]Indexed[string]{{0, "a"}, {2, "c"}}, even.ToSlice())
+
+	assert.Equal([]Indexed[int]{}, WithIndex(FromSlice([]int{})).ToSlice())
+
+	// the index counts the elements of the source, not of the downstream.
+	skipped := WithIndex(FromRange(1, 10, 1)).Skip(3).Limit(2).ToSlice()
+	assert.Equal([]Indexed[int]{{3, 4}, {4, 5}}, skipped)
+}
+
+func TestUnzip(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestUnzip")
+
+	pairs := FromSlice([]Pair[int, string]{
+		{First: 1, Second: "a"},
+		{First: 2, Second: "b"},
+	})
+
+	firsts, seconds := Unzip(pairs)
+	assert.Equal([]int{1, 2}, firsts)
+	assert.Equal([]string{"a", "b"}, seconds)
+
+	// round trip with Zip
+	firsts, seconds = Unzip(Zip(FromSlice([]int{1, 2, 3}), FromSlice([]string{"a", "b", "c"})))
+	assert.Equal([]int{1, 2, 3}, firsts)
+	assert.Equal([]string{"a", "b", "c"}, seconds)
+
+	firsts, seconds = Unzip(FromSlice([]Pair[int, string]{}))
+	assert.Equal([]int{}, firsts)
+	assert.Equal([]string{}, seconds)
+}
+
+func TestZipWith(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestZipWith")
+
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{10, 20, 30, 40})
+
+	s := ZipWith(s1, s2, func(a, b int) int {
+		return a + b
+	})
+
+	assert.Equal([]int{11, 22, 33}, s.ToSlice())
+}
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSum")
+
+	assert.Equal(6, Sum(FromSlice([]int{1, 2, 3})))
+	assert.Equal(4.5, Sum(FromSlice([]float64{1.5, 3.0})))
+	assert.Equal(0, Sum(FromSlice([]int{})))
+}
+
+func TestAverage(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAverage")
+
+	assert.Equal(2.5, Average(FromSlice([]int{1, 2, 3, 4})))
+	assert.Equal(2.25, Average(FromSlice([]float64{1.5, 3.0})))
+	assert.Equal(0.0, Average(FromSlice([]int{})))
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSummarize")
+
+	stats := Summarize(FromSlice([]int{3, -1, 4, 2}))
+	assert.Equal(Stats[int]{Count: 4, Sum: 8, Min: -1, Max: 4, Average: 2, Empty: false}, stats)
+
+	floats := Summarize(FromSlice([]float64{1.5, 3.0}))
+	assert.Equal(1.5, floats.Min)
+	assert.Equal(3.0, floats.Max)
+	assert.Equal(2.25, floats.Average)
+
+	evaluated := 0
+	lazy := FromSlice([]int{1, 2, 3}).Map(func(item int) int {
+		evaluated++
+		return item
+	})
+	assert.Equal(6, Summarize(lazy).Sum)
+	assert.Equal(3, evaluated)
+
+	assert.Equal(Stats[int]{Empty: true}, Summarize(FromSlice([]int{})))
+}
+
+func TestStream_WithContext(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_WithContext")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	n := 0
+	generator := func() func() (int, bool) {
+		return func() (int, bool) {
+			n++
+			if n == 3 {
+				cancel()
+			}
+			return n, true
+		}
+	}
+
+	// the generator is infinite, the stream stops after ctx is canceled.
+	s := Generate(generator).WithContext(ctx).Map(func(n int) int {
+		return n * 10
+	})
+
+	assert.Equal([]int{10, 20, 30}, s.ToSlice())
+
+	s2 := FromSlice([]int{1, 2, 3}).WithContext(ctx)
+	assert.Equal([]int{}, s2.ToSlice())
+	assert.Equal(0, s2.Count())
+}
+
+func TestDistinctComparable(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDistinctComparable")
+
+	nums := FromSlice([]int{1, 2, 2, 3, 1, 3, 3})
+	assert.Equal([]int{1, 2, 3}, DistinctComparable(nums).ToSlice())
+	assert.Equal([]int{1, 2, 2, 3, 1, 3, 3}, nums.ToSlice())
+
+	type Person struct {
+		Id   string
+		Name string
+	}
+
+	people := FromSlice([]Person{
+		{Id: "001", Name: "Tom"},
+		{Id: "002", Name: "Jim"},
+		{Id: "001", Name: "Tom"},
+	})
+
+	assert.Equal([]Person{{Id: "001", Name: "Tom"}, {Id: "002", Name: "Jim"}}, DistinctComparable(people).ToSlice())
+}
+
+func BenchmarkDistinct(b *testing.B) {
+	data := make([]int, 1000)
+	for i := 0; i < len(data); i++ {
+		data[i] = i % 100
+	}
+	s := FromSlice(data)
+
+	b.Run("Distinct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if s.Distinct().Count() != 100 {
+				b.Fatal("unexpected distinct count")
+			}
+		}
+	})
+
+	b.Run("DistinctComparable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if DistinctComparable(s).Count() != 100 {
+				b.Fatal("unexpected distinct count")
+			}
+		}
+	})
+}
+
+func TestStream_CountMatch(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_CountMatch")
+
+	isEven := func(item int) bool { return item%2 == 0 }
+
+	assert.Equal(50, FromRange(1, 100, 1).CountMatch(isEven))
+	assert.Equal(0, FromSlice([]int{1, 3}).CountMatch(isEven))
+	assert.Equal(0, FromSlice([]int{}).CountMatch(isEven))
+	assert.Equal(5, Iterate(1, func(n int) int { return n + 1 }).Limit(10).CountMatch(isEven))
+}
+
+func BenchmarkCount(b *testing.B) {
+	data := make([]int, 10000)
+	for i := 0; i < len(data); i++ {
+		data[i] = i
+	}
+	s := FromSlice(data).Filter(func(item int) bool { return item%2 == 0 })
+
+	b.Run("ToSlice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if len(s.ToSlice()) != 5000 {
+				b.Fatal("unexpected count")
+			}
+		}
+	})
+
+	b.Run("Count", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if s.Count() != 5000 {
+				b.Fatal("unexpected count")
+			}
+		}
+	})
+}
+
+func TestDistinctBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDistinctBy")
+
+	type User struct {
+		Id   int
+		Name string
+	}
+
+	users := FromSlice([]User{
+		{Id: 1, Name: "Tom"},
+		{Id: 2, Name: "Jim"},
+		{Id: 1, Name: "Tommy"},
+		{Id: 3, Name: "Mike"},
+		{Id: 2, Name: "Jimmy"},
+	})
+
+	result := DistinctBy(users, func(u User) int {
+		return u.Id
+	})
+
+	expected := []User{
+		{Id: 1, Name: "Tom"},
+		{Id: 2, Name: "Jim"},
+		{Id: 3, Name: "Mike"},
+	}
+
+	assert.Equal(expected, result.ToSlice())
+}
+
+type minMaxSum struct {
+	min, max, sum int
+	count         int
+}
+
+type minMaxSumReducer struct{}
+
+func (minMaxSumReducer) Supplier() minMaxSum {
+	return minMaxSum{}
+}
+
+func (minMaxSumReducer) Accumulator(result minMaxSum, item int) minMaxSum {
+	if result.count == 0 || item < result.min {
+		result.min = item
+	}
+	if result.count == 0 || item > result.max {
+		result.max = item
+	}
+	result.sum += item
+	result.count++
+
+	return result
+}
+
+func TestCollectWith(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCollectWith")
+
+	var reducer Reducer[int, minMaxSum] = minMaxSumReducer{}
+
+	assert.Equal(minMaxSum{min: -2, max: 7, sum: 10, count: 4}, CollectWith(FromSlice([]int{3, -2, 7, 2}), reducer))
+	assert.Equal(minMaxSum{}, CollectWith(FromSlice([]int{}), reducer))
+
+	// the result is supplied freshly for each reduction.
+	set := NewReducer(func() map[string]struct{} {
+		return map[string]struct{}{}
+	}, func(result map[string]struct{}, item string) map[string]struct{} {
+		result[item] = struct{}{}
+		return result
+	})
+
+	assert.Equal(map[string]struct{}{"a": {}, "b": {}}, CollectWith(FromSlice([]string{"a", "b", "a"}), set))
+	assert.Equal(map[string]struct{}{"c": {}}, CollectWith(FromSlice([]string{"c"}), set))
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFold")
+
+	s := FromSlice([]string{"a", "bb", "ccc"})
+
+	length := Fold(s, 0, func(acc int, item string) int {
+		return acc + len(item)
+	})
+	assert.Equal(6, length)
+
+	lengths := Fold(s, map[string]int{}, func(acc map[string]int, item string) map[string]int {
+		acc[item] = len(item)
+		return acc
+	})
+	assert.Equal(map[string]int{"a": 1, "bb": 2, "ccc": 3}, lengths)
+
+	assert.Equal(10, Fold(FromSlice([]string{}), 10, func(acc int, item string) int {
+		return acc + len(item)
+	}))
+}
+
+func TestScanTo(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestScanTo")
+
+	stream := FromSlice([]string{"a", "bb", "ccc"})
+
+	lengths := ScanTo(stream, 0, func(acc int, item string) int {
+		return acc + len(item)
+	})
+
+	assert.Equal([]int{0, 1, 3, 6}, lengths.ToSlice())
+}
+
+func TestAssociate(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestAssociate")
+
+	s := FromSlice([]string{"a", "bb", "cc", "ddd"})
+
+	m := Associate(s, func(item string) (int, string) {
+		return len(item), item
+	})
+
+	assert.Equal(map[int]string{1: "a", 2: "cc", 3: "ddd"}, m)
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestJoin")
+
+	assert.Equal("a,b,c", Join(FromSlice([]string{"a", "b", "c"}), ","))
+	assert.Equal(",b,", Join(FromSlice([]string{"", "b", ""}), ","))
+	assert.Equal("a", Join(FromSlice([]string{"a"}), ","))
+	assert.Equal("", Join(FromSlice([]string{}), ","))
+}
+
+func TestJoinWith(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestJoinWith")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	result := JoinWith(s, " | ", func(n int) string {
+		return fmt.Sprint(n * 10)
+	})
+
+	assert.Equal("10 | 20 | 30", result)
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFlatten")
+
+	s := FromSlice([][]int{{1, 2}, {}, {3}, nil, {4, 5}})
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, Flatten(s).ToSlice())
+	assert.Equal([]int{1, 2, 3}, Flatten(s).Limit(3).ToSlice())
+	assert.Equal([]int{}, Flatten(FromSlice([][]int{})).ToSlice())
+
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+	assert.Equal(original.ToSlice(), Flatten(Chunk(original, 2)).ToSlice())
+}
+
+func TestContain(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestContain")
+
+	s := FromSlice([]string{"a", "b", "c"})
+
+	assert.Equal(true, Contain(s, "b"))
+	assert.Equal(false, Contain(s, "d"))
+	assert.Equal(false, Contain(FromSlice([]string{}), ""))
+}
+
+func TestMaxBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMaxBy")
+
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	users := FromSlice([]User{
+		{Name: "Tom", Age: -10},
+		{Name: "Jim", Age: -2},
+		{Name: "Mike", Age: -2},
+		{Name: "Bob", Age: -30},
+	})
+
+	oldest, ok := MaxBy(users, func(u User) int { return u.Age })
+	assert.Equal(User{Name: "Jim", Age: -2}, oldest)
+	assert.Equal(true, ok)
+
+	_, ok = MaxBy(FromSlice([]User{}), func(u User) int { return u.Age })
+	assert.Equal(false, ok)
+}
+
+func TestMinBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMinBy")
+
+	words := FromSlice([]string{"ccc", "a", "bb", "d"})
+
+	shortest, ok := MinBy(words, func(s string) int { return len(s) })
+	assert.Equal("a", shortest)
+	assert.Equal(true, ok)
+
+	_, ok = MinBy(FromSlice([]string{}), func(s string) int { return len(s) })
+	assert.Equal(false, ok)
+}
+
+func TestSortedBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSortedBy")
+
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	users := FromSlice([]User{
+		{Name: "Tom", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Bob", Age: 10},
+	})
+
+	sorted := SortedBy(users, func(u User) int { return u.Age })
+
+	assert.Equal([]User{
+		{Name: "Jim", Age: 10},
+		{Name: "Bob", Age: 10},
+		{Name: "Tom", Age: 20},
+		{Name: "Mike", Age: 20},
+	}, sorted.ToSlice())
+
+	assert.Equal("Tom", users.ToSlice()[0].Name)
+}
+
+func TestSortedByDesc(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSortedByDesc")
+
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	users := FromSlice([]User{
+		{Name: "Tom", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Bob", Age: 10},
+	})
+
+	sorted := SortedByDesc(users, func(u User) int { return u.Age })
+
+	assert.Equal([]User{
+		{Name: "Tom", Age: 20},
+		{Name: "Mike", Age: 20},
+		{Name: "Jim", Age: 10},
+		{Name: "Bob", Age: 10},
+	}, sorted.ToSlice())
+}
+
+func TestToSet(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestToSet")
+
+	s := FromSlice([]int{1, 2, 2, 3, 1})
+
+	assert.Equal(map[int]struct{}{1: {}, 2: {}, 3: {}}, ToSet(s))
+	assert.Equal(map[int]struct{}{}, ToSet(FromSlice([]int{})))
+}
+
+func TestFrequency(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFrequency")
+
+	words := FromSlice(strings.Fields("the quick fox jumps over the lazy dog the end"))
+	assert.Equal(map[string]int{"the": 3, "quick": 1, "fox": 1, "jumps": 1, "over": 1, "lazy": 1, "dog": 1, "end": 1}, Frequency(words))
+
+	lazy := FromRange(1, 10, 1).Map(func(n int) int { return n % 3 })
+	assert.Equal(map[int]int{0: 3, 1: 4, 2: 3}, Frequency(lazy))
+
+	assert.Equal(map[int]int{}, Frequency(FromSlice([]int{})))
+}
+
+func TestInterleave(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestInterleave")
+
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{4})
+	s3 := FromSlice([]int{5, 6})
+
+	assert.Equal([]int{1, 4, 5, 2, 6, 3}, Interleave(s1, s2, s3).ToSlice())
+	assert.Equal([]int{4, 1, 2, 3}, Interleave(s2, FromSlice([]int{}), s1).ToSlice())
+	assert.Equal([]int{1, 2, 3}, Interleave(s1).ToSlice())
+	assert.Equal([]int{}, Interleave[int]().ToSlice())
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestTee")
+
+	evaluated := 0
+	s := FromSlice([]int{1, 2, 3, 4}).Map(func(item int) int {
+		evaluated++
+		return item * 10
+	})
+
+	streams := Tee(s, 2)
+	assert.Equal(2, len(streams))
+
+	assert.Equal([]int{20, 40}, streams[0].Filter(func(item int) bool { return item%20 == 0 }).ToSlice())
+	assert.Equal(100, Sum(streams[1]))
+	assert.Equal([]int{10, 20, 30, 40}, streams[0].ToSlice())
+	assert.Equal(4, evaluated)
+
+	// infinite stream is buffered on demand.
+	nums := Tee(Iterate(1, func(item int) int { return item + 1 }), 2)
+	assert.Equal([]int{1, 2, 3}, nums[0].Limit(3).ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5}, nums[1].Limit(5).ToSlice())
+
+	assert.Equal(0, len(Tee(FromSlice([]int{1}), 0)))
+	assert.Equal([]int{}, Tee(FromSlice([]int{}), 1)[0].ToSlice())
+}
+
+func TestTopGroups(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestTopGroups")
+
+	errs := FromSlice([]string{"timeout", "refused", "timeout", "reset", "refused", "timeout", "eof"})
+	identity := func(item string) string { return item }
+
+	assert.Equal([]GroupCount[string]{
+		{Key: "timeout", Count: 3},
+		{Key: "refused", Count: 2},
+	}, TopGroups(errs, identity, 2))
+
+	assert.Equal([]GroupCount[string]{
+		{Key: "timeout", Count: 3},
+		{Key: "refused", Count: 2},
+		{Key: "reset", Count: 1},
+		{Key: "eof", Count: 1},
+	}, TopGroups(errs, identity, -1))
+
+	assert.Equal(4, len(TopGroups(errs, identity, 10)))
+	assert.Equal([]GroupCount[string]{}, TopGroups(errs, identity, 0))
+	assert.Equal([]GroupCount[int]{}, TopGroups(FromSlice([]int{}), func(item int) int { return item }, 3))
+
+	parity := TopGroups(FromRange(1, 5, 1), func(item int) bool { return item%2 == 0 }, 1)
+	assert.Equal([]GroupCount[bool]{{Key: false, Count: 3}}, parity)
+}
+
+func TestStream_ForEachChunk(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachChunk")
+
+	batches := [][]int{}
+	err := FromRange(1, 7, 1).ForEachChunk(3, func(batch []int) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	assert.IsNil(err)
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}, {7}}, batches)
+
+	// it stops at the first error.
+	pulled, calls := 0, 0
+	errStop := errors.New("stop")
+	err = FromRange(1, 100, 1).Peek(func(int) { pulled++ }).ForEachChunk(2, func(batch []int) error {
+		calls++
+		if batch[0] == 3 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(errStop, err)
+	assert.Equal(2, calls)
+	assert.Equal(4, pulled)
+
+	calls = 0
+	err = FromSlice([]int{}).ForEachChunk(2, func(batch []int) error {
+		calls++
+		return nil
+	})
+	assert.IsNil(err)
+	assert.Equal(0, calls)
+
+	// the size is checked by ProcessBatches.
+	defer func() {
+		assert.Equal("stream.ProcessBatches: param size should be positive", recover())
+	}()
+	FromSlice([]int{1}).ForEachChunk(0, func(batch []int) error { return nil })
+}
+
+func TestProcessBatches(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestProcessBatches")
+
+	batches := [][]int{}
+	collect := func(batch []int) error {
+		batches = append(batches, batch)
+		return nil
+	}
+
+	err := ProcessBatches(FromRange(1, 7, 1), 3, collect)
+	assert.IsNil(err)
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}, {7}}, batches)
+
+	batches = [][]int{}
+	err = ProcessBatches(FromSlice([]int{}), 3, collect)
+	assert.IsNil(err)
+	assert.Equal([][]int{}, batches)
+
+	// stops at the first error
+	pulled := 0
+	calls := 0
+	errFailed := errors.New("failed")
+	err = ProcessBatches(FromRange(1, 100, 1).Peek(func(int) { pulled++ }), 2, func(batch []int) error {
+		calls++
+		if batch[0] == 3 {
+			return errFailed
+		}
+		return nil
+	})
+	assert.Equal(errFailed, err)
+	assert.Equal(2, calls)
+	assert.Equal(4, pulled)
+
+	// the error of the final partial batch is returned
+	err = ProcessBatches(FromRange(1, 3, 1), 2, func(batch []int) error {
+		if len(batch) < 2 {
+			return errFailed
+		}
+		return nil
+	})
+	assert.Equal(errFailed, err)
+
+	defer func() {
+		assert.IsNotNil(recover())
+	}()
+	ProcessBatches(FromRange(1, 3, 1), 0, collect)
+}
+
+func TestProcessBatchesHugeSize(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestProcessBatchesHugeSize")
+
+	batches := [][]int{}
+	collect := func(batch []int) error {
+		batches = append(batches, batch)
+		return nil
+	}
+
+	err := ProcessBatches(FromSlice([]int{1, 2, 3}), math.MaxInt, collect)
+	assert.IsNil(err)
+	assert.Equal([][]int{{1, 2, 3}}, batches)
+
+	batches = [][]int{}
+	err = FromRange(1, 3, 1).ForEachChunk(1<<40, collect)
+	assert.IsNil(err)
+	assert.Equal([][]int{{1, 2, 3}}, batches)
+}
+
+func TestMapE(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapE")
+
+	result, err := MapE(FromSlice([]string{"1", "2", "3"}), strconv.Atoi)
+	assert.IsNil(err)
+	assert.Equal([]int{1, 2, 3}, result)
+
+	pulled := 0
+	result, err = MapE(FromSlice([]string{"1", "x", "3"}).Peek(func(string) { pulled++ }), strconv.Atoi)
+	assert.IsNotNil(err)
+	assert.Equal([]int(nil), result)
+	assert.Equal(2, pulled)
+
+	result, err = MapE(FromSlice([]string{}), strconv.Atoi)
+	assert.IsNil(err)
+	assert.Equal([]int{}, result)
+}
+
+func TestMapParallel(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapParallel")
+
+	var running, maxRunning int32
+	mapper := func(n int) string {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			peak := atomic.LoadInt32(&maxRunning)
+			if current <= peak || atomic.CompareAndSwapInt32(&maxRunning, peak, current) {
+				break
+			}
+		}
+
+		// the later elements finish first.
+		time.Sleep(time.Duration(10-n) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		return strconv.Itoa(n * 10)
+	}
+
+	result := MapParallel(FromRange(1, 9, 1), 3, mapper).ToSlice()
+	assert.Equal([]string{"10", "20", "30", "40", "50", "60", "70", "80", "90"}, result)
+	assert.Equal(true, atomic.LoadInt32(&maxRunning) <= 3)
+
+	double := func(n int) int { return n * 2 }
+	assert.Equal([]int{2, 4, 6}, MapParallel(FromSlice([]int{1, 2, 3}), 0, double).ToSlice())
+	assert.Equal([]int{2, 4, 6}, MapParallel(FromSlice([]int{1, 2, 3}), 10, double).ToSlice())
+	assert.Equal([]int{}, MapParallel(FromSlice([]int{}), 2, double).ToSlice())
+	assert.Equal([]int{2, 4, 6}, MapParallel(FromSlice([]int{1, 2, 3}), math.MaxInt, double).ToSlice())
+
+	// a batch larger than the capacity allocated up front.
+	large := MapParallel(FromRange(1, 2000, 1), 1500, double).ToSlice()
+	assert.Equal(2000, len(large))
+	assert.Equal(4000, large[1999])
+
+	// it pulls one batch at a time.
+	pulled := 0
+	s := FromRange(1, 100, 1).Peek(func(int) { pulled++ })
+	assert.Equal([]int{2, 4, 6}, MapParallel(s, 2, double).Limit(3).ToSlice())
+	assert.Equal(4, pulled)
+
+	defer func() {
+		assert.Equal("boom", recover())
+	}()
+	MapParallel(FromSlice([]int{1, 2, 3}), 2, func(n int) int {
+		if n == 2 {
+			panic("boom")
+		}
+		return n
+	}).ToSlice()
+}
+
+func TestGroupAdjacent(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGroupAdjacent")
+
+	isEven := func(n int) bool { return n%2 == 0 }
+
+	s := FromSlice([]int{1, 3, 2, 4, 6, 5, 8})
+	assert.Equal([][]int{{1, 3}, {2, 4, 6}, {5}, {8}}, GroupAdjacent(s, isEven).ToSlice())
+
+	assert.Equal([][]int{{1}}, GroupAdjacent(FromSlice([]int{1}), isEven).ToSlice())
+	assert.Equal([][]int{}, GroupAdjacent(FromSlice([]int{}), isEven).ToSlice())
+
+	// it pulls only the elements needed to complete the groups.
+	pulled := 0
+	lazy := FromRange(1, 100, 1).Peek(func(int) { pulled++ })
+	groups := GroupAdjacent(lazy, func(n int) int { return (n - 1) / 3 }).Limit(2).ToSlice()
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}}, groups)
+	assert.Equal(7, pulled)
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEqual")
+
+	assert.Equal(true, Equal(FromSlice([]int{1, 2, 3}), FromRange(1, 3, 1)))
+	assert.Equal(true, Equal(FromSlice([]int{}), Empty[int]()))
+	assert.Equal(false, Equal(FromSlice([]int{1, 2, 3}), FromSlice([]int{1, 2, 4})))
+	assert.Equal(false, Equal(FromSlice([]int{1, 2}), FromSlice([]int{1, 2, 3})))
+	assert.Equal(false, Equal(FromRange(1, 3, 1), FromRange(1, 2, 1)))
+
+	// it short-circuits on the first difference.
+	pulled := 0
+	lazy := FromRange(1, 100, 1).Peek(func(int) { pulled++ })
+	assert.Equal(false, Equal(lazy, FromSlice([]int{1, 5, 3})))
+	assert.Equal(2, pulled)
+
+	// it works with infinite streams as long as they differ.
+	assert.Equal(false, Equal(RepeatInfinite(1), FromSlice([]int{1, 1})))
+
+	type point struct{ xs []int }
+	sameLength := func(x, y point) bool { return len(x.xs) == len(y.xs) }
+	assert.Equal(true, EqualBy(FromSlice([]point{{[]int{1}}}), FromSlice([]point{{[]int{2}}}), sameLength))
+	assert.Equal(false, EqualBy(FromSlice([]point{{[]int{1}}}), FromSlice([]point{{}}), sameLength))
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCompact")
+
+	assert.Equal([]string{"a", "b"}, Compact(FromSlice([]string{"", "a", "", "b", ""})).ToSlice())
+	assert.Equal([]int{1, 2}, Compact(FromSlice([]int{0, 1, 0, 2})).ToSlice())
+	assert.Equal([]int{}, Compact(FromSlice([]int{0, 0})).ToSlice())
+
+	zero := 0
+	assert.Equal([]*int{&zero}, Compact(FromSlice([]*int{nil, &zero, nil})).ToSlice())
+
+	type point struct{ x, y int }
+	assert.Equal([]point{{x: 1}}, Compact(FromSlice([]point{{}, {x: 1}, {}})).ToSlice())
+}
+
+func TestDedup(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDedup")
+
+	assert.Equal([]int{1, 2, 1, 3}, Dedup(FromSlice([]int{1, 1, 2, 2, 2, 1, 3, 3})).ToSlice())
+	assert.Equal([]int{0}, Dedup(FromSlice([]int{0, 0})).ToSlice())
+	assert.Equal([]int{}, Dedup(FromSlice([]int{})).ToSlice())
+	assert.Equal([]int{1, 2, 3}, Dedup(FromSlice([]int{1, 2, 3})).ToSlice())
+}
+
+func TestDedupBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDedupBy")
+
+	words := FromSlice([]string{"apple", "avocado", "banana", "blueberry", "apricot"})
+
+	result := DedupBy(words, func(item string) byte { return item[0] })
+
+	assert.Equal([]string{"apple", "banana", "apricot"}, result.ToSlice())
+}